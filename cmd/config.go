@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	config "github.com/phrase/phrase-cli/cmd/internal"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	initConfigValidate()
+	initConfigShow()
+
+	rootCmd.AddCommand(ConfigCmd)
+}
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate the configuration",
+}
+
+func initConfigValidate() {
+	var configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for mistakes",
+		Long:  "Check sources and targets, their file formats, project ids and locale ids, and report every problem found with the path of the offending value.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdConfigValidate := config.ConfigValidateCommand{
+				Config: *Config,
+			}
+			err := cmdConfigValidate.Run()
+			if err != nil {
+				HandleError(err)
+			}
+		},
+	}
+	ConfigCmd.AddCommand(configValidateCmd)
+}
+
+func initConfigShow() {
+	var configShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration",
+		Long:  "Show the configuration after the config file, environment variables and flags are merged, and where every setting came from. Access tokens are redacted.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdConfigShow := config.ConfigShowCommand{
+				Config:  *Config,
+				Origins: ConfigOrigins,
+			}
+			err := cmdConfigShow.Run()
+			if err != nil {
+				HandleError(err)
+			}
+		},
+	}
+	ConfigCmd.AddCommand(configShowCmd)
+}
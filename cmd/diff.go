@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+
+	diff "github.com/phrase/phrase-cli/cmd/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	initDiff()
+}
+
+func initDiff() {
+	params := viper.New()
+	var diffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Show the keys a pull would change",
+		Long: `Download every target into memory and show which keys a pull would add, remove or change, without writing any files.
+
+Exit codes:
+  0  no differences were found, or --exit-code is not given
+  1  files differ and --exit-code is given
+  2  the files could not be compared`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdDiff := diff.DiffCommand{
+				Config:             *Config,
+				Branch:             branchParam(params),
+				UseLocalBranchName: params.GetBool("use-local-branch-name"),
+				Locale:             params.GetString("locale"),
+				OnlyTag:            onlyTagParam(params),
+				ExitCode:           params.GetBool("exit-code"),
+			}
+			err := cmdDiff.Run()
+			if found, ok := err.(*diff.DiffFoundError); ok {
+				// differences are the result of the diff, not an error
+				os.Exit(found.ExitCode())
+			}
+			if err != nil {
+				HandleError(err)
+			}
+		},
+	}
+	rootCmd.AddCommand(diffCmd)
+
+	AddFlag(diffCmd, "string", "branch", "b", "branch", false)
+	AddFlag(diffCmd, "bool", "use-local-branch-name", "", "use local branch name", false)
+	AddFlag(diffCmd, "string", "locale", "", "Only compare files of the locales with these names, codes or ids, separated by commas", false)
+	AddFlag(diffCmd, "string", "only-tag", "", "Only compare files of these tags, separated by commas", false)
+	AddFlag(diffCmd, "string", "tag", "", "Only compare files of these tags, separated by commas", false)
+	diffCmd.Flags().MarkDeprecated("tag", "use --only-tag instead")
+	AddFlag(diffCmd, "bool", "exit-code", "", "Exit with 1 if any file differs, errors exit with 2", false)
+	params.BindPFlags(diffCmd.Flags())
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phrase/phrase-go"
+)
+
+// testAPI starts a server answering requests to the paths of routes with
+// their JSON body and makes it the host of the global configuration.
+func testAPI(t *testing.T, routes map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	previous := Config
+	Config = &phrase.Config{Credentials: phrase.Credentials{Host: server.URL, Token: "token"}}
+	t.Cleanup(func() { Config = previous })
+
+	return server
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/phrase/phrase-cli/cmd/internal/ratelimit"
+	"github.com/phrase/phrase-go"
+)
+
+// Auth is the context passed to all API calls. Credentials are not part of it,
+// as every client sends the access token it was created for.
+var Auth = context.Background()
+
+type clientKey struct {
+	host, token string
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[clientKey]*phrase.APIClient{}
+)
+
+// newClient returns the client for the host and access token of the global
+// configuration.
+func newClient() *phrase.APIClient {
+	return clientFor(Config.Credentials.Host, Config.Credentials.Token)
+}
+
+// clientFor returns an API client sending requests to host, authenticated by
+// token. Empty values fall back to the global configuration. Clients are
+// reused for the same host and token.
+func clientFor(host, token string) *phrase.APIClient {
+	if host == "" {
+		host = Config.Credentials.Host
+	}
+	if token == "" {
+		token = Config.Credentials.Token
+	}
+
+	key := clientKey{host: strings.TrimRight(host, "/"), token: token}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if client, ok := clients[key]; ok {
+		return client
+	}
+
+	cfg := phrase.NewConfiguration()
+	cfg.SetUserAgent(Config.UserAgent)
+	cfg.HTTPClient = &http.Client{Transport: &conditionalTransport{base: ratelimit.DefaultTransport}}
+	if key.host != "" {
+		cfg.BasePath = key.host
+	}
+	if key.token != "" {
+		cfg.AddDefaultHeader("Authorization", "token "+key.token)
+	}
+
+	client := phrase.NewAPIClient(cfg)
+	clients[key] = client
+	return client
+}
+
+type ifNoneMatchKey struct{}
+
+// withIfNoneMatch returns a context which makes requests conditional on the
+// resource no longer matching etag.
+func withIfNoneMatch(ctx context.Context, etag string) context.Context {
+	return context.WithValue(ctx, ifNoneMatchKey{}, etag)
+}
+
+// conditionalTransport adds the If-None-Match header to requests whose
+// context carries an ETag, see withIfNoneMatch.
+type conditionalTransport struct {
+	base http.RoundTripper
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if etag, ok := req.Context().Value(ifNoneMatchKey{}).(string); ok && etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/phrase/phrase-cli/cmd/internal/interpolate"
+	"gopkg.in/yaml.v2"
+)
+
+// includeKey is the key of the push and pull sections listing files whose
+// settings are merged into the section.
+const includeKey = "include"
+
+// ConfigFile is the path of the config file which was read. Relative paths of
+// included files are resolved against its directory, or against the working
+// directory if it is empty.
+var ConfigFile string
+
+// includeDir returns the absolute directory relative include paths of the
+// config file are resolved against.
+func includeDir() (string, error) {
+	if ConfigFile == "" {
+		return os.Getwd()
+	}
+	return filepath.Abs(filepath.Dir(ConfigFile))
+}
+
+// expandSection resolves the includes of the push or pull section raw and
+// replaces ${VAR} and ${VAR:-default} in all of its values with environment
+// variables. name is the name of the section, "push" or "pull".
+func expandSection(name string, raw []byte) ([]byte, error) {
+	var section map[interface{}]interface{}
+	if err := yaml.Unmarshal(raw, &section); err != nil {
+		return nil, err
+	}
+
+	dir, err := includeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	section, err = resolveIncludes(name, section, dir, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := interpolate.Value(map[interface{}]interface{}{name: section}, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(section)
+}
+
+// resolveIncludes merges the files listed under the include key of section
+// into it. Relative paths are resolved against dir. Lists such as sources and
+// targets of included files come before the ones of section, all other
+// settings of section take precedence. visiting holds the files currently
+// being included to detect cycles.
+func resolveIncludes(name string, section map[interface{}]interface{}, dir string, visiting map[string]bool) (map[interface{}]interface{}, error) {
+	value, ok := section[includeKey]
+	if !ok {
+		return section, nil
+	}
+	delete(section, includeKey)
+
+	paths, err := includePaths(value)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[interface{}]interface{}{}
+	for _, path := range paths {
+		path, err := interpolate.Env(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", name, includeKey, err)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		included, err := readInclude(name, path, visiting)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeSections(merged, included)
+	}
+
+	return mergeSections(merged, section), nil
+}
+
+func includePaths(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		paths := []string{}
+		for _, path := range v {
+			s, ok := path.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a file name or a list of file names", includeKey)
+			}
+			paths = append(paths, s)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("%s must be a file name or a list of file names", includeKey)
+	}
+}
+
+// readInclude reads the section name of the included file at path and
+// resolves its includes.
+func readInclude(name string, path string, visiting map[string]bool) (map[interface{}]interface{}, error) {
+	if visiting[path] {
+		return nil, fmt.Errorf("%s is included recursively", path)
+	}
+	visiting[path] = true
+	defer delete(visiting, path)
+
+	section, _, err := includedSection(name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveIncludes(name, section, filepath.Dir(path), visiting)
+}
+
+// includedSection reads the section name of the included file at path. The
+// file either contains the settings of the section itself, or is a complete
+// config file with a phrase key, so that a single file can be shared by push
+// and pull. The returned prefix is the key path of the section in the file.
+func includedSection(name string, path string) (section map[interface{}]interface{}, prefix string, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not read included file: %s", err)
+	}
+
+	var file map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, "", fmt.Errorf("could not parse included file %s: %s", path, err)
+	}
+
+	if phrase, ok := file["phrase"].(map[interface{}]interface{}); ok {
+		section, _ = phrase[name].(map[interface{}]interface{})
+		if section == nil {
+			section = map[interface{}]interface{}{}
+		}
+		return section, "phrase." + name + ".", nil
+	}
+
+	return file, "", nil
+}
+
+// listEntry locates an entry of a list such as sources by its key path in the
+// file it is defined in. File is empty for the config file itself.
+type listEntry struct {
+	Path string
+	File string
+}
+
+func (entry listEntry) String() string {
+	if entry.File == "" {
+		return entry.Path
+	}
+	return entry.Path + " in " + entry.File
+}
+
+// listEntries returns where each entry of the list key, such as sources, of
+// the push or pull section raw was defined, in the order of the list after
+// includes are resolved.
+func listEntries(name string, key string, raw []byte) ([]listEntry, error) {
+	var section map[interface{}]interface{}
+	if err := yaml.Unmarshal(raw, &section); err != nil {
+		return nil, err
+	}
+
+	dir, err := includeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return listEntriesOf(name, key, section, dir, "phrase."+name+".", "", map[string]bool{})
+}
+
+// listEntriesOf works like listEntries for section, which is read from file
+// with the section at prefix. Like in resolveIncludes, entries of included
+// files come first.
+func listEntriesOf(name, key string, section map[interface{}]interface{}, dir, prefix, file string, visiting map[string]bool) ([]listEntry, error) {
+	entries := []listEntry{}
+
+	if value, ok := section[includeKey]; ok {
+		paths, err := includePaths(value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			path, err := interpolate.Env(path)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %s", name, includeKey, err)
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if visiting[path] {
+				return nil, fmt.Errorf("%s is included recursively", path)
+			}
+
+			included, includedPrefix, err := includedSection(name, path)
+			if err != nil {
+				return nil, err
+			}
+
+			visiting[path] = true
+			includedEntries, err := listEntriesOf(name, key, included, filepath.Dir(path), includedPrefix, path, visiting)
+			delete(visiting, path)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, includedEntries...)
+		}
+	}
+
+	if file != "" {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				file = rel
+			}
+		}
+	}
+
+	list, _ := section[key].([]interface{})
+	for i := range list {
+		entries = append(entries, listEntry{Path: fmt.Sprintf("%s%s[%d]", prefix, key, i), File: file})
+	}
+
+	return entries, nil
+}
+
+// mergeSections returns the settings of base overridden by the ones of
+// section, except for lists, which are concatenated.
+func mergeSections(base, section map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := map[interface{}]interface{}{}
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range section {
+		baseList, baseIsList := merged[key].([]interface{})
+		list, isList := value.([]interface{})
+		if baseIsList && isList {
+			merged[key] = append(append([]interface{}{}, baseList...), list...)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
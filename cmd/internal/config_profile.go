@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/phrase/phrase-go"
+	"gopkg.in/yaml.v2"
+)
+
+// ProfileEnv is the environment variable selecting a profile if the
+// --profile flag is not given.
+const ProfileEnv = "PHRASE_PROFILE"
+
+// profilesKey is the key of the phrase section defining named profiles.
+const profilesKey = "profiles"
+
+var configFileNames = []string{".phrase.yml", ".phraseapp.yml"}
+
+// FindConfigFile returns the config file to read for the --config flag value
+// flagPath, and a description of how it was found. It looks the file up like
+// phrase.ReadConfig does, so that config show reports the file which is read.
+// path is empty if there is no config file.
+func FindConfigFile(flagPath string) (path string, origin string, err error) {
+	if flagPath != "" {
+		return flagPath, "--config flag", nil
+	}
+
+	if envPath := os.Getenv("PHRASEAPP_CONFIG"); envPath != "" {
+		if _, err := os.Stat(envPath); err != nil {
+			if os.IsNotExist(err) {
+				err = fmt.Errorf("file %q (from PHRASEAPP_CONFIG environment variable) doesn't exist", envPath)
+			}
+			return "", "", err
+		}
+		return envPath, "PHRASEAPP_CONFIG environment variable", nil
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		for _, name := range configFileNames {
+			if _, err := os.Stat(filepath.Join(wd, name)); err == nil {
+				return filepath.Join(wd, name), "working directory", nil
+			}
+		}
+	}
+
+	for _, name := range configFileNames {
+		if _, err := os.Stat(filepath.Join(homeDir(), name)); err == nil {
+			return filepath.Join(homeDir(), name), "home directory", nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// homeDir returns the directory of the fallback config file, which is
+// $HOME, or %HomePath% on Windows.
+func homeDir() string {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("HomePath")
+	}
+	return os.Getenv("HOME")
+}
+
+// ConfigProfile is the profile that was merged into the config.
+type ConfigProfile struct {
+	Name   string
+	Origin string
+	// Branch is the branch used by push and pull if none is given.
+	Branch string
+	// Settings are the keys of the settings overridden by the profile, in
+	// the notation of ConfigOrigins.
+	Settings []string
+}
+
+// ReadConfig reads the config file at path like phrase.ReadConfig and merges
+// the profile name into it. Settings of the profile override the ones of the
+// config, maps such as push and pull are merged, and lists such as sources
+// and targets are replaced. An empty name selects no profile. The global
+// locale mapping is moved into the push and pull sections.
+func ReadConfig(path string, name string) (*phrase.Config, *ConfigProfile, error) {
+	if path == "" {
+		if name != "" {
+			return nil, nil, fmt.Errorf("profile %q was selected, but there is no config file", name)
+		}
+		return &phrase.Config{}, nil, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read config file: %s", err)
+	}
+
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, nil, err
+	}
+
+	var profile *ConfigProfile
+	for _, key := range []string{"phrase", "phraseapp"} {
+		section, ok := document[key].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		profile, err = applyProfile(section, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := moveLocaleMapping(section); err != nil {
+			return nil, nil, err
+		}
+		break
+	}
+	if name != "" && profile == nil {
+		return nil, nil, fmt.Errorf("profile %q is not defined in %s", name, path)
+	}
+
+	content, err = yaml.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configs := map[string]*phrase.Config{}
+	if err := yaml.Unmarshal(content, configs); err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range []string{"phrase", "phraseapp"} {
+		if config, found := configs[key]; found {
+			return config, profile, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("'phrase' key is missing in config")
+}
+
+// applyProfile removes the profiles from section and merges the profile name
+// into it. It returns nil if name is empty.
+func applyProfile(section map[interface{}]interface{}, name string) (*ConfigProfile, error) {
+	profiles, _ := section[profilesKey].(map[interface{}]interface{})
+	delete(section, profilesKey)
+
+	if name == "" {
+		return nil, nil
+	}
+
+	raw, found := profiles[name]
+	if !found {
+		available := []string{}
+		for key := range profiles {
+			available = append(available, fmt.Sprint(key))
+		}
+		sort.Strings(available)
+
+		if len(available) == 0 {
+			return nil, fmt.Errorf("profile %q is not defined, the config has no profiles", name)
+		}
+		return nil, fmt.Errorf("profile %q is not defined, available profiles: %s", name, strings.Join(available, ", "))
+	}
+
+	settings, ok := raw.(map[interface{}]interface{})
+	if !ok && raw != nil {
+		return nil, fmt.Errorf("profile %q must be a map of settings", name)
+	}
+
+	profile := &ConfigProfile{Name: name}
+	if branch, ok := settings["branch"]; ok {
+		profile.Branch, ok = branch.(string)
+		if !ok {
+			return nil, fmt.Errorf("branch of profile %q must be a string", name)
+		}
+		delete(settings, "branch")
+	}
+
+	for key, value := range settings {
+		if key == "defaults" {
+			defaults, _ := value.(map[interface{}]interface{})
+			for path, values := range defaults {
+				values, _ := values.(map[interface{}]interface{})
+				for setting := range values {
+					profile.Settings = append(profile.Settings, fmt.Sprintf("defaults.%v.%v", path, setting))
+				}
+			}
+			continue
+		}
+		profile.Settings = append(profile.Settings, fmt.Sprint(key))
+	}
+	sort.Strings(profile.Settings)
+
+	mergeProfile(section, settings)
+	return profile, nil
+}
+
+// mergeProfile merges the settings of a profile into section. Maps are
+// merged recursively, all other values are replaced.
+func mergeProfile(section, settings map[interface{}]interface{}) {
+	for key, value := range settings {
+		base, baseIsMap := section[key].(map[interface{}]interface{})
+		override, isMap := value.(map[interface{}]interface{})
+		if baseIsMap && isMap {
+			mergeProfile(base, override)
+		} else {
+			section[key] = value
+		}
+	}
+}
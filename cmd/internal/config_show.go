@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigOrigins records where the effective values of the global settings
+// came from.
+type ConfigOrigins struct {
+	File       string
+	FileOrigin string
+	Profile    *ConfigProfile
+	Settings   map[string]string
+}
+
+// NewConfigOrigins attributes every setting of config that is set to the
+// config file at path.
+func NewConfigOrigins(config *phrase.Config, path string, fileOrigin string) *ConfigOrigins {
+	origins := &ConfigOrigins{
+		File:       path,
+		FileOrigin: fileOrigin,
+		Settings:   map[string]string{},
+	}
+
+	for _, setting := range configSettings(config) {
+		if setting.Value != "" {
+			origins.Settings[setting.Key] = "config file"
+		}
+	}
+
+	return origins
+}
+
+// SetProfile attributes the settings overridden by profile to it.
+func (origins *ConfigOrigins) SetProfile(profile *ConfigProfile) {
+	origins.Profile = profile
+	for _, key := range profile.Settings {
+		origins.Set(key, fmt.Sprintf("profile %s", profile.Name))
+	}
+}
+
+// Set records that the value of key came from origin.
+func (origins *ConfigOrigins) Set(key string, origin string) {
+	origins.Settings[key] = origin
+}
+
+type ConfigShowCommand struct {
+	phrase.Config
+	Origins *ConfigOrigins
+}
+
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+}
+
+type configEvent struct {
+	Event         string                 `json:"event"`
+	File          string                 `json:"file,omitempty"`
+	FileOrigin    string                 `json:"file_origin,omitempty"`
+	Profile       string                 `json:"profile,omitempty"`
+	ProfileOrigin string                 `json:"profile_origin,omitempty"`
+	Settings      []*configSetting       `json:"settings"`
+	Push          map[string]interface{} `json:"push,omitempty"`
+	Pull          map[string]interface{} `json:"pull,omitempty"`
+}
+
+func (cmd *ConfigShowCommand) Run() error {
+	event, err := newConfigEvent(&cmd.Config, cmd.Origins)
+	if err != nil {
+		return err
+	}
+
+	print.Event(event)
+	return event.print(print.Out())
+}
+
+// PrintConfig writes the effective configuration with redacted access tokens
+// to w.
+func PrintConfig(w io.Writer, config *phrase.Config, origins *ConfigOrigins) error {
+	event, err := newConfigEvent(config, origins)
+	if err != nil {
+		return err
+	}
+	return event.print(w)
+}
+
+func newConfigEvent(config *phrase.Config, origins *ConfigOrigins) (*configEvent, error) {
+	event := &configEvent{
+		Event:      "config",
+		File:       origins.File,
+		FileOrigin: origins.FileOrigin,
+	}
+
+	for _, setting := range configSettings(config) {
+		setting.Origin = origins.Settings[setting.Key]
+		if setting.Origin == "" {
+			setting.Origin = "default"
+		}
+		event.Settings = append(event.Settings, setting)
+	}
+
+	if profile := origins.Profile; profile != nil {
+		event.Profile = profile.Name
+		event.ProfileOrigin = profile.Origin
+		if profile.Branch != "" {
+			event.Settings = append(event.Settings, &configSetting{
+				Key:    "branch",
+				Value:  profile.Branch,
+				Origin: fmt.Sprintf("profile %s", profile.Name),
+			})
+		}
+	}
+
+	var err error
+	if event.Push, err = redactedSection("push", config.Sources); err != nil {
+		return nil, fmt.Errorf("could not read push section: %s", err)
+	}
+	if event.Pull, err = redactedSection("pull", config.Targets); err != nil {
+		return nil, fmt.Errorf("could not read pull section: %s", err)
+	}
+
+	return event, nil
+}
+
+func (event *configEvent) print(w io.Writer) error {
+	if event.File != "" {
+		fmt.Fprintf(w, "Config file: %s (%s)\n\n", event.File, event.FileOrigin)
+	} else {
+		fmt.Fprint(w, "Config file: none\n\n")
+	}
+	if event.Profile != "" {
+		fmt.Fprintf(w, "Profile: %s (%s)\n\n", event.Profile, event.ProfileOrigin)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tORIGIN")
+	for _, setting := range event.Settings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.Key, orDash(setting.Value), setting.Origin)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name    string
+		content map[string]interface{}
+	}{
+		{"push", event.Push},
+		{"pull", event.Pull},
+	}
+
+	for _, section := range sections {
+		if section.content == nil {
+			continue
+		}
+
+		content, err := yaml.Marshal(map[string]interface{}{section.name: section.content})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		fmt.Fprint(w, string(content))
+	}
+
+	return nil
+}
+
+// configSettings returns the global settings of config with redacted
+// credentials, followed by the defaults sorted by key.
+func configSettings(config *phrase.Config) []*configSetting {
+	settings := []*configSetting{
+		{Key: "access_token", Value: RedactToken(config.Credentials.Token)},
+		{Key: "host", Value: config.Credentials.Host},
+		{Key: "username", Value: config.Credentials.Username},
+		{Key: "tfa", Value: boolSetting(config.Credentials.TFA)},
+		{Key: "debug", Value: boolSetting(config.Debug)},
+		{Key: "project_id", Value: config.DefaultProjectID},
+		{Key: "file_format", Value: config.DefaultFileFormat},
+		{Key: "page", Value: intSetting(config.Page)},
+		{Key: "per_page", Value: intSetting(config.PerPage)},
+	}
+
+	defaults := []*configSetting{}
+	for path, values := range config.Defaults {
+		for key, value := range values {
+			defaults = append(defaults, &configSetting{
+				Key:   fmt.Sprintf("defaults.%s.%s", path, key),
+				Value: fmt.Sprint(value),
+			})
+		}
+	}
+	sort.Slice(defaults, func(i, j int) bool {
+		return defaults[i].Key < defaults[j].Key
+	})
+
+	return append(settings, defaults...)
+}
+
+func boolSetting(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+func intSetting(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return fmt.Sprint(*i)
+}
+
+// redactedSection parses the push or pull section of a config file as push
+// and pull see it, with includes resolved and variables replaced, and redacts
+// all access tokens in it. name is the name of the section.
+func redactedSection(name string, raw []byte) (map[string]interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	raw, err := expandSection(name, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var section map[string]interface{}
+	if err := yaml.Unmarshal(raw, &section); err != nil {
+		return nil, err
+	}
+
+	return redactTokens(section).(map[string]interface{}), nil
+}
+
+// redactTokens returns value with all access tokens redacted and all maps
+// converted to map[string]interface{}, so that it can be encoded as JSON.
+func redactTokens(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, child := range v {
+			result[key] = redactValue(key, child)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, child := range v {
+			result[fmt.Sprint(key)] = redactValue(fmt.Sprint(key), child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			result[i] = redactTokens(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func redactValue(key string, value interface{}) interface{} {
+	if token, ok := value.(string); ok && key == "access_token" {
+		return RedactToken(token)
+	}
+	return redactTokens(value)
+}
+
+// RedactToken hides all but the last four characters of an access token, or
+// all of it if it is too short to reveal anything.
+func RedactToken(token string) string {
+	switch {
+	case token == "":
+		return ""
+	case len(token) < 16:
+		return strings.Repeat("*", 8)
+	default:
+		return strings.Repeat("*", 8) + token[len(token)-4:]
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/phrase/phrase-go"
+)
+
+func TestNewConfigEvent_expandsSections(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-config-show_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	included := "targets:\n  - file: ./shared/<locale_code>.json\n    access_token: abcdefghijklmnop1234\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "targets.yml"), []byte(included), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the include is relative to the config file, not the working directory
+	previous := ConfigFile
+	ConfigFile = filepath.Join(dir, ".phrase.yml")
+	defer func() { ConfigFile = previous }()
+
+	os.Setenv("TEST_CONFIG_SHOW_DIR", "app")
+	defer os.Unsetenv("TEST_CONFIG_SHOW_DIR")
+
+	config := &phrase.Config{
+		Targets: []byte("include: targets.yml\ntargets:\n  - file: ./${TEST_CONFIG_SHOW_DIR}/<locale_code>.json\n"),
+	}
+
+	event, err := newConfigEvent(config, &ConfigOrigins{Settings: map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"targets": []interface{}{
+			map[string]interface{}{"file": "./shared/<locale_code>.json", "access_token": "********1234"},
+			map[string]interface{}{"file": "./app/<locale_code>.json"},
+		},
+	}
+	if !reflect.DeepEqual(event.Pull, expected) {
+		t.Errorf("expected pull section %v, got %v", expected, event.Pull)
+	}
+	if event.Push != nil {
+		t.Errorf("expected no push section, got %v", event.Push)
+	}
+}
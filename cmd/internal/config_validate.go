@@ -0,0 +1,314 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+)
+
+type ConfigValidateCommand struct {
+	phrase.Config
+}
+
+// configProblem is a mistake in the config file, located by the YAML path of
+// the offending value.
+type configProblem struct {
+	Event string `json:"event"`
+	Path  string `json:"path"`
+	// File is the included file the value is defined in, if it is not
+	// defined in the config file itself.
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// configValidator collects the problems of a config and caches what it looked
+// up in Phrase.
+type configValidator struct {
+	problems []*configProblem
+	formats  map[string]*phrase.Format
+	// projects maps a project id to an error if it cannot be accessed.
+	projects map[string]error
+	locales  map[string][]*phrase.Locale
+}
+
+func (cmd *ConfigValidateCommand) Run() error {
+	Config = &cmd.Config
+
+	v := &configValidator{
+		projects: map[string]error{},
+		locales:  map[string][]*phrase.Locale{},
+	}
+
+	formats, err := formatsByApiName(newClient())
+	if err != nil {
+		v.add("phrase", "could not retrieve the list of formats from Phrase: %s", err)
+	}
+	v.formats = formats
+
+	if len(cmd.Config.Sources) == 0 && len(cmd.Config.Targets) == 0 {
+		v.add("phrase", "neither push sources nor pull targets are configured")
+	}
+
+	if len(cmd.Config.Sources) > 0 {
+		v.validateSources(cmd.Config)
+	}
+
+	if len(cmd.Config.Targets) > 0 {
+		v.validateTargets(cmd.Config)
+	}
+
+	for _, problem := range v.problems {
+		print.Event(problem)
+		if problem.File != "" {
+			print.Failure("%s in %s: %s", problem.Path, problem.File, problem.Message)
+		} else {
+			print.Failure("%s: %s", problem.Path, problem.Message)
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in the configuration", len(v.problems))
+	}
+
+	print.Success("The configuration is valid.")
+	return nil
+}
+
+func (v *configValidator) add(path string, format string, args ...interface{}) {
+	v.problems = append(v.problems, &configProblem{
+		Event:   "config_problem",
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *configValidator) validateSources(config phrase.Config) {
+	pushConfig, err := PushConfigFromConfig(config)
+	if err != nil {
+		v.add("phrase.push", "%s", err)
+		return
+	}
+
+	sources, err := SourcesFromConfig(config)
+	if err != nil {
+		v.add("phrase.push.sources", "%s", err)
+		return
+	}
+
+	entries := v.listEntries("push", "sources", config.Sources, len(pushConfig.Sources))
+
+	// SourcesFromConfig drops empty entries, so count them to find the path
+	valid := 0
+	names := map[string]string{}
+	starts := make([]int, len(entries))
+	defer v.locate(starts, entries)
+	for i, raw := range pushConfig.Sources {
+		path := entries[i].Path
+		starts[i] = len(v.problems)
+		if raw == nil {
+			v.add(path, "empty source")
+			continue
+		}
+		source := sources[valid]
+		valid++
+
+		v.validateName(entries[i], source.Name, names)
+
+		if err := source.CheckPreconditions(); err != nil {
+			v.add(path+".file", "%s", err)
+		}
+
+		formatPath := configValuePath(path, raw.Params != nil && raw.Params.FileFormat.IsSet(), raw.FileFormat != "", "file_format")
+		v.validateFormat(formatPath, source.GetFileFormat())
+
+		projectPath := configValuePath(path, false, raw.ProjectID != "", "project_id")
+		if !v.validateProject(projectPath, source.ProjectID, source.Client()) {
+			continue
+		}
+
+		if source.Params.LocaleId.IsSet() {
+			v.validateLocale(path+".params.locale_id", source.Params.LocaleId.Value(), source.ProjectID, source.Client())
+		}
+	}
+}
+
+func (v *configValidator) validateTargets(config phrase.Config) {
+	pullConfig, err := PullConfigFromConfig(config)
+	if err != nil {
+		v.add("phrase.pull", "%s", err)
+		return
+	}
+
+	if _, err := pullConfig.GetFileMode(); err != nil {
+		v.add("phrase.pull.file_mode", "%s", err)
+	}
+
+	targets, err := TargetsFromConfig(config)
+	if err != nil {
+		v.add("phrase.pull.targets", "%s", err)
+		return
+	}
+
+	entries := v.listEntries("pull", "targets", config.Targets, len(pullConfig.Targets))
+
+	// TargetsFromConfig drops empty entries, so count them to find the path
+	valid := 0
+	names := map[string]string{}
+	starts := make([]int, len(entries))
+	defer v.locate(starts, entries)
+	for i, raw := range pullConfig.Targets {
+		path := entries[i].Path
+		starts[i] = len(v.problems)
+		if raw == nil {
+			v.add(path, "empty target")
+			continue
+		}
+		target := targets[valid]
+		valid++
+
+		v.validateName(entries[i], target.Name, names)
+
+		if err := target.CheckPreconditions(); err != nil {
+			v.add(path+".file", "%s", err)
+		}
+
+		formatPath := configValuePath(path, raw.Params != nil && raw.Params.FileFormat.Value() != "", raw.FileFormat != "", "file_format")
+		v.validateFormat(formatPath, target.GetFormat())
+
+		projectPath := configValuePath(path, false, raw.ProjectID != "", "project_id")
+		if !v.validateProject(projectPath, target.ProjectID, target.Client()) {
+			continue
+		}
+
+		if localeID := target.GetLocaleID(); localeID != "" {
+			v.validateLocale(path+".params.locale_id", localeID, target.ProjectID, target.Client())
+		}
+	}
+}
+
+// listEntries returns where the n entries of the list key of the push or pull
+// section raw are defined. If that can't be determined, the entries are
+// located by their index in the list with the entries of included files.
+func (v *configValidator) listEntries(name, key string, raw []byte, n int) []listEntry {
+	entries, err := listEntries(name, key, raw)
+	if err == nil && len(entries) == n {
+		return entries
+	}
+
+	entries = make([]listEntry, n)
+	for i := range entries {
+		entries[i].Path = fmt.Sprintf("phrase.%s.%s[%d]", name, key, i)
+	}
+	return entries
+}
+
+// locate sets the file of the problems of the entries, as their paths are
+// relative to the file the entry is defined in. The problems of entries[i]
+// start at index starts[i].
+func (v *configValidator) locate(starts []int, entries []listEntry) {
+	for i, entry := range entries {
+		end := len(v.problems)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+
+		for _, problem := range v.problems[starts[i]:end] {
+			if problem.Path == entry.Path || strings.HasPrefix(problem.Path, entry.Path+".") {
+				problem.File = entry.File
+			}
+		}
+	}
+}
+
+// configValuePath returns the path of a value of a source or target which
+// may be given in its params, in the source or target itself, or fall back
+// to the top level of the config.
+func configValuePath(path string, inParams bool, inEntry bool, name string) string {
+	switch {
+	case inParams:
+		return path + ".params." + name
+	case inEntry:
+		return path + "." + name
+	default:
+		return "phrase." + name
+	}
+}
+
+// validateName checks that name of entry is unique. names maps the names seen
+// so far to the entries using them.
+func (v *configValidator) validateName(entry listEntry, name string, names map[string]string) {
+	if name == "" {
+		return
+	}
+	if previous, found := names[name]; found {
+		v.add(entry.Path+".name", "name %q is already used by %s", name, previous)
+		return
+	}
+	names[name] = entry.String()
+}
+
+func (v *configValidator) validateFormat(path string, format string) {
+	switch {
+	case format == "":
+		v.add(path, "no file format given")
+	case v.formats == nil:
+		// the formats could not be retrieved, which was reported already
+	case v.formats[format] == nil:
+		v.add(path, "format %q is not supported by Phrase", format)
+	}
+}
+
+// validateProject checks that projectID exists and returns false otherwise.
+func (v *configValidator) validateProject(path string, projectID string, client *phrase.APIClient) bool {
+	if projectID == "" {
+		v.add(path, "no project id given")
+		return false
+	}
+
+	err, checked := v.projects[projectID]
+	if !checked {
+		_, response, showErr := client.ProjectsApi.ProjectShow(Auth, projectID, nil)
+		switch {
+		case showErr == nil:
+		case response != nil && response.StatusCode == 404:
+			err = fmt.Errorf("project %q does not exist or cannot be accessed with the configured access token", projectID)
+		default:
+			err = fmt.Errorf("could not check project %q: %s", projectID, showErr)
+		}
+		v.projects[projectID] = err
+	}
+
+	if err != nil {
+		v.add(path, "%s", err)
+		return false
+	}
+	return true
+}
+
+func (v *configValidator) validateLocale(path string, localeID string, projectID string, client *phrase.APIClient) {
+	if strings.Contains(localeID, "<") {
+		// resolved per file from placeholders
+		return
+	}
+
+	locales, ok := v.locales[projectID]
+	if !ok {
+		var err error
+		locales, _, err = RemoteLocales(client, LocaleCacheKey{ProjectID: projectID})
+		if err != nil {
+			v.add(path, "could not retrieve the locales of project %q: %s", projectID, err)
+			return
+		}
+		v.locales[projectID] = locales
+	}
+
+	for _, locale := range locales {
+		if locale.Id == localeID || locale.Name == localeID || locale.Code == localeID {
+			return
+		}
+	}
+
+	v.add(path, "locale %q does not exist in project %q", localeID, projectID)
+}
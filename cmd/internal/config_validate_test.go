@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/phrase/phrase-go"
+)
+
+func TestConfigValidator_localeIDAgreesWithPull(t *testing.T) {
+	testAPI(t, map[string]string{
+		"GET /projects/p1":         `{"id": "p1", "name": "Project"}`,
+		"GET /projects/p1/locales": `[{"id": "l-de", "name": "German", "code": "de"}]`,
+	})
+
+	for _, localeID := range []string{"l-de", "German", "de"} {
+		config := phrase.Config{
+			DefaultProjectID: "p1",
+			Targets: []byte(`
+targets:
+  - file: ./locales/de.json
+    params:
+      file_format: json
+      locale_id: ` + localeID + `
+`),
+		}
+
+		v := &configValidator{
+			formats:  map[string]*phrase.Format{"json": {ApiName: "json"}},
+			projects: map[string]error{},
+			locales:  map[string][]*phrase.Locale{},
+		}
+		v.validateTargets(config)
+		for _, problem := range v.problems {
+			t.Errorf("%s: unexpected problem %s: %s", localeID, problem.Path, problem.Message)
+		}
+
+		targets, err := TargetsFromConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		target := targets[0]
+		target.RemoteLocales, _, err = RemoteLocales(target.Client(), LocaleCacheKey{ProjectID: target.ProjectID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		locale, err := target.localeForRemote()
+		if err != nil {
+			t.Errorf("%s: %s", localeID, err)
+		} else if locale.Id != "l-de" {
+			t.Errorf("%s: expected locale l-de, got %s", localeID, locale.Id)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/phrase/phrase-cli/cmd/internal/placeholders"
+	"github.com/phrase/phrase-go"
+)
+
+// projectNames caches the names of projects by id for the <project_name>
+// placeholder.
+var projectNames = struct {
+	sync.Mutex
+	names map[string]string
+}{names: map[string]string{}}
+
+// contextValues returns the values of the project and branch placeholders of
+// pattern. The project name is only retrieved if pattern uses it.
+func contextValues(pattern string, client *phrase.APIClient, projectID string, branch string) (map[string]string, error) {
+	values := map[string]string{
+		"project_id": projectID,
+		"branch":     branch,
+	}
+
+	if strings.Contains(pattern, "<project_name>") {
+		name, err := projectName(client, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve the name of project %q for <project_name>: %s", projectID, err)
+		}
+		values["project_name"] = name
+	}
+
+	return values, nil
+}
+
+func projectName(client *phrase.APIClient, projectID string) (string, error) {
+	projectNames.Lock()
+	defer projectNames.Unlock()
+
+	if name, ok := projectNames.names[projectID]; ok {
+		return name, nil
+	}
+
+	project, _, err := client.ProjectsApi.ProjectShow(Auth, projectID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	projectNames.names[projectID] = project.Name
+	return project.Name, nil
+}
+
+// filePattern returns the file pattern of source with the project and
+// branch placeholders replaced.
+func (source *Source) filePattern() (string, error) {
+	if !placeholders.ContainsContextPlaceholder(source.File) {
+		return source.File, nil
+	}
+
+	values, err := contextValues(source.File, source.Client(), source.ProjectID, source.branch)
+	if err != nil {
+		return "", err
+	}
+	return placeholders.Replace(source.File, values), nil
+}
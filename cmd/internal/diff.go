@@ -0,0 +1,255 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	ct "github.com/daviddengcn/go-colortext"
+	"github.com/phrase/phrase-cli/cmd/internal/keys"
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+)
+
+const (
+	// ExitCodeDiffFound is the exit code of diff with --exit-code if any
+	// file differs.
+	ExitCodeDiffFound = 1
+	// ExitCodeDiffError is the exit code of diff if the comparison failed,
+	// so that it can't be mistaken for differences.
+	ExitCodeDiffError = 2
+)
+
+// DiffFoundError is returned by diff with --exit-code if any file differs.
+type DiffFoundError struct {
+	Files int
+}
+
+func (err *DiffFoundError) Error() string {
+	return fmt.Sprintf("%d file(s) differ from Phrase", err.Files)
+}
+
+func (err *DiffFoundError) ExitCode() int {
+	return ExitCodeDiffFound
+}
+
+// DiffError is an error which kept diff from comparing the files.
+type DiffError struct {
+	Err error
+}
+
+func (err *DiffError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *DiffError) ExitCode() int {
+	return ExitCodeDiffError
+}
+
+type DiffCommand struct {
+	phrase.Config
+	Branch             string
+	UseLocalBranchName bool
+	Locale             string
+	OnlyTag            string
+	ExitCode           bool
+}
+
+type diffEvent struct {
+	Event      string       `json:"event"`
+	File       string       `json:"file"`
+	LocaleID   string       `json:"locale_id"`
+	LocaleName string       `json:"locale_name,omitempty"`
+	Tag        string       `json:"tag,omitempty"`
+	Changes    []*keyChange `json:"changes,omitempty"`
+	// Binary is true if the format cannot be compared by keys and only the
+	// file contents differ.
+	Binary bool `json:"binary,omitempty"`
+}
+
+// keyChange is a key a pull would add, remove or change.
+type keyChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	Local  string `json:"local,omitempty"`
+	Remote string `json:"remote,omitempty"`
+}
+
+func (cmd *DiffCommand) Run() error {
+	err := cmd.run()
+	if _, found := err.(*DiffFoundError); err != nil && !found {
+		return &DiffError{Err: err}
+	}
+	return err
+}
+
+func (cmd *DiffCommand) run() error {
+	if cmd.Config.Debug {
+		// suppresses content output
+		cmd.Config.Debug = false
+		Debug = true
+	}
+	Config = &cmd.Config
+
+	targets, err := TargetsFromConfig(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	selection := NewSelection("", cmd.Locale, cmd.OnlyTag)
+	targets, err = selection.SelectTargets(targets)
+	if err != nil {
+		return err
+	}
+
+	branchName, err := usedBranchName(cmd.UseLocalBranchName, cmd.Branch)
+	if err != nil {
+		return err
+	}
+	cmd.Branch = branchName
+
+	projectIdToLocales, err := LocalesForProjects(targets, cmd.Branch)
+	if err != nil {
+		return err
+	}
+
+	selected, differing := 0, 0
+	for _, target := range targets {
+		if err := target.CheckPreconditions(); err != nil {
+			return err
+		}
+
+		target.RemoteLocales = projectIdToLocales[LocaleCacheKey{target.ProjectID, cmd.Branch}]
+		target.branch = cmd.Branch
+		if len(target.RemoteLocales) == 0 {
+			// the branch does not exist in this target's project
+			continue
+		}
+
+		localeFiles, err := target.LocaleFiles()
+		if err != nil {
+			return err
+		}
+		selected += len(localeFiles)
+
+		for _, localeFile := range localeFiles {
+			event, err := target.diff(target.Client(), localeFile, cmd.Branch)
+			if err != nil {
+				return fmt.Errorf("%s for %s", err, localeFile.RelPath())
+			}
+
+			if event != nil {
+				differing++
+				event.print()
+				print.Event(event)
+			}
+		}
+	}
+
+	if selection.filtersFiles() && selected == 0 {
+		return selection.errNoSelectedFiles()
+	}
+
+	if differing == 0 {
+		fmt.Fprintln(print.Out(), "No differences found.")
+		return nil
+	}
+
+	if cmd.ExitCode {
+		return &DiffFoundError{Files: differing}
+	}
+
+	return nil
+}
+
+// diff downloads localeFile into memory and compares it with the local file.
+// It returns nil if a pull would not change the file.
+func (target *Target) diff(client *phrase.APIClient, localeFile *LocaleFile, branch string) (*diffEvent, error) {
+	remote, err := target.Download(client, localeFile, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := ioutil.ReadFile(localeFile.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if bytes.Equal(local, remote) {
+		return nil, nil
+	}
+
+	event := &diffEvent{
+		Event:      "diff",
+		File:       localeFile.RelPath(),
+		LocaleID:   localeFile.ID,
+		LocaleName: localeFile.Name,
+		Tag:        localeFile.Tag,
+	}
+
+	format, ok := keys.DetectFormat(localeFile.FileFormat, localeFile.Path)
+	if !ok {
+		event.Binary = true
+		return event, nil
+	}
+
+	remoteKeys, err := keys.Parse(format, remote)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse downloaded file: %s", err)
+	}
+
+	localKeys := map[string]string{}
+	if len(local) > 0 {
+		localKeys, err = keys.Parse(format, local)
+		if err != nil {
+			// compare the whole file if it cannot be parsed locally
+			event.Binary = true
+			return event, nil
+		}
+	}
+
+	diff := keys.Compare(localKeys, remoteKeys)
+	if diff.Empty() {
+		// only formatting differs
+		return nil, nil
+	}
+
+	for _, key := range diff.Removed {
+		event.Changes = append(event.Changes, &keyChange{Key: key, Change: "removed", Local: localKeys[key]})
+	}
+	for _, key := range diff.Changed {
+		event.Changes = append(event.Changes, &keyChange{Key: key, Change: "changed", Local: localKeys[key], Remote: remoteKeys[key]})
+	}
+	for _, key := range diff.Added {
+		event.Changes = append(event.Changes, &keyChange{Key: key, Change: "added", Remote: remoteKeys[key]})
+	}
+	sort.Slice(event.Changes, func(i, j int) bool {
+		return event.Changes[i].Key < event.Changes[j].Key
+	})
+
+	return event, nil
+}
+
+// print writes the changes of a single file as a unified diff of its keys.
+func (event *diffEvent) print() {
+	fmt.Fprintf(print.Out(), "--- %s (local)\n", event.File)
+	fmt.Fprintf(print.Out(), "+++ %s (Phrase, locale %s)\n", event.File, event.LocaleName)
+
+	if event.Binary {
+		fmt.Fprintln(print.Out(), "Files differ")
+	}
+
+	for _, change := range event.Changes {
+		if change.Change != "added" {
+			print.WithColor(ct.Red, "-%s: %s", change.Key, strconv.Quote(change.Local))
+		}
+		if change.Change != "removed" {
+			print.WithColor(ct.Green, "+%s: %s", change.Key, strconv.Quote(change.Remote))
+		}
+	}
+
+	fmt.Fprintln(print.Out())
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"os"
+
+	"github.com/phrase/phrase-go"
+)
+
+// Events written for machine-readable output, see print.Event.
+
+type uploadEvent struct {
+	Event         string                `json:"event"`
+	File          string                `json:"file"`
+	UploadID      string                `json:"upload_id,omitempty"`
+	LocaleID      string                `json:"locale_id,omitempty"`
+	LocaleCode    string                `json:"locale_code,omitempty"`
+	LocaleName    string                `json:"locale_name,omitempty"`
+	CreatedLocale bool                  `json:"created_locale,omitempty"`
+	State         string                `json:"state,omitempty"`
+	Skipped       bool                  `json:"skipped,omitempty"`
+	Unchanged     bool                  `json:"unchanged,omitempty"`
+	Summary       *phrase.UploadSummary `json:"summary,omitempty"`
+	Error         string                `json:"error,omitempty"`
+}
+
+type downloadEvent struct {
+	Event      string `json:"event"`
+	File       string `json:"file"`
+	LocaleID   string `json:"locale_id"`
+	LocaleCode string `json:"locale_code,omitempty"`
+	LocaleName string `json:"locale_name,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Bytes      int64  `json:"bytes"`
+	Unchanged  bool   `json:"unchanged,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type plannedUploadEvent struct {
+	Event        string `json:"event"`
+	File         string `json:"file"`
+	LocaleID     string `json:"locale_id,omitempty"`
+	LocaleCode   string `json:"locale_code,omitempty"`
+	LocaleName   string `json:"locale_name,omitempty"`
+	CreateLocale string `json:"create_locale,omitempty"`
+	Tags         string `json:"tags,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	Format       string `json:"format"`
+}
+
+type plannedDownloadEvent struct {
+	Event      string `json:"event"`
+	File       string `json:"file"`
+	Action     string `json:"action"`
+	LocaleID   string `json:"locale_id"`
+	LocaleName string `json:"locale_name,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	Format     string `json:"format,omitempty"`
+}
+
+func newDownloadEvent(localeFile *LocaleFile, written bool, err error) downloadEvent {
+	event := downloadEvent{
+		Event:      "download",
+		File:       localeFile.RelPath(),
+		LocaleID:   localeFile.ID,
+		LocaleCode: localeFile.Code,
+		LocaleName: localeFile.Name,
+		Tag:        localeFile.Tag,
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+		return event
+	}
+
+	event.Unchanged = !written
+	if info, statErr := os.Stat(localeFile.Path); statErr == nil {
+		event.Bytes = info.Size()
+	}
+
+	return event
+}
@@ -0,0 +1,109 @@
+package interpolate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// variable matches ${NAME} and ${NAME:-default}, as well as the escaped form
+// $${...} which stands for a literal ${...}.
+var variable = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// LookupFunc returns the value of a variable and whether it is set, like
+// os.LookupEnv.
+type LookupFunc func(name string) (string, bool)
+
+// Env replaces all variables in s with values from the environment.
+func Env(s string) (string, error) {
+	return String(s, os.LookupEnv)
+}
+
+// String replaces ${NAME} in s with the value of NAME. ${NAME:-default} is
+// replaced with default if NAME is unset or empty. $${NAME} is replaced with
+// a literal ${NAME}. It is an error to use a variable that is not set and
+// has no default.
+func String(s string, lookup LookupFunc) (string, error) {
+	missing := []string{}
+
+	result := variable.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		groups := variable.FindStringSubmatch(match)
+		name, hasDefault, fallback := groups[1], groups[2] != "", groups[3]
+
+		value, ok := lookup(name)
+		switch {
+		case ok && value != "":
+			return value
+		case hasDefault:
+			return fallback
+		case ok:
+			return value
+		default:
+			missing = append(missing, name)
+			return match
+		}
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+
+	return result, nil
+}
+
+// Value replaces variables in all strings contained in v, which is the
+// result of unmarshalling YAML or JSON. Maps and slices are modified in
+// place. Errors are prefixed with the path of the offending value, like
+// "sources[0].file".
+func Value(v interface{}, lookup LookupFunc) (interface{}, error) {
+	return value(v, "", lookup)
+}
+
+func value(v interface{}, path string, lookup LookupFunc) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		result, err := String(val, lookup)
+		if err != nil && path != "" {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		return result, err
+	case map[interface{}]interface{}:
+		for key, child := range val {
+			result, err := value(child, joinPath(path, fmt.Sprint(key)), lookup)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = result
+		}
+	case map[string]interface{}:
+		for key, child := range val {
+			result, err := value(child, joinPath(path, key), lookup)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = result
+		}
+	case []interface{}:
+		for i, child := range val {
+			result, err := value(child, fmt.Sprintf("%s[%d]", path, i), lookup)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = result
+		}
+	}
+
+	return v, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
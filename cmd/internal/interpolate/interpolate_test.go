@@ -0,0 +1,87 @@
+package interpolate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lookup(name string) (string, bool) {
+	env := map[string]string{
+		"PROJECT_ID": "abc123",
+		"PREFIX":     "apps/shop",
+		"EMPTY":      "",
+	}
+	value, ok := env[name]
+	return value, ok
+}
+
+func TestString(t *testing.T) {
+	tests := map[string]string{
+		"plain":                          "plain",
+		"${PROJECT_ID}":                  "abc123",
+		"./${PREFIX}/<locale_code>.json": "./apps/shop/<locale_code>.json",
+		"${MISSING:-fallback}":           "fallback",
+		"${EMPTY:-fallback}":             "fallback",
+		"${EMPTY}":                       "",
+		"${PROJECT_ID:-fallback}":        "abc123",
+		"$${PROJECT_ID}":                 "${PROJECT_ID}",
+		"$PROJECT_ID":                    "$PROJECT_ID",
+	}
+
+	for input, expect := range tests {
+		result, err := String(input, lookup)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", input, err)
+			continue
+		}
+		if result != expect {
+			t.Errorf("%q: expected %q, got %q", input, expect, result)
+		}
+	}
+}
+
+func TestString_missing(t *testing.T) {
+	_, err := String("${MISSING}/${ALSO_MISSING}", lookup)
+	if err == nil || err.Error() != "environment variable MISSING, ALSO_MISSING is not set" {
+		t.Errorf("expected error about missing variables, got %v", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	input := map[interface{}]interface{}{
+		"sources": []interface{}{
+			map[interface{}]interface{}{
+				"file":       "./${PREFIX}/<locale_code>.json",
+				"project_id": "${PROJECT_ID}",
+				"params": map[interface{}]interface{}{
+					"update_translations": true,
+				},
+			},
+		},
+	}
+
+	expect := map[interface{}]interface{}{
+		"sources": []interface{}{
+			map[interface{}]interface{}{
+				"file":       "./apps/shop/<locale_code>.json",
+				"project_id": "abc123",
+				"params": map[interface{}]interface{}{
+					"update_translations": true,
+				},
+			},
+		},
+	}
+
+	result, err := Value(input, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("expected %v, got %v", expect, result)
+	}
+
+	_, err = Value(map[interface{}]interface{}{"sources": []interface{}{"${MISSING}"}}, lookup)
+	if err == nil || err.Error() != "sources[0]: environment variable MISSING is not set" {
+		t.Errorf("expected error with path, got %v", err)
+	}
+}
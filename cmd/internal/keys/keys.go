@@ -0,0 +1,155 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"gopkg.in/yaml.v2"
+)
+
+// Format is a locale file format the keys of which can be parsed.
+type Format string
+
+const (
+	JSON       Format = "json"
+	YAML       Format = "yaml"
+	Properties Format = "properties"
+	Strings    Format = "strings"
+)
+
+// DetectFormat returns the format to parse a locale file with. fileFormat is
+// the name of a Phrase file format, path is only used if fileFormat is not
+// known.
+func DetectFormat(fileFormat, path string) (Format, bool) {
+	switch {
+	case fileFormat == "properties":
+		return Properties, true
+	case fileFormat == "strings":
+		return Strings, true
+	case strings.HasPrefix(fileFormat, "yml"):
+		return YAML, true
+	case strings.Contains(fileFormat, "json"), fileFormat == "i18next", fileFormat == "go_i18n":
+		return JSON, true
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, true
+	case ".yml", ".yaml":
+		return YAML, true
+	case ".properties":
+		return Properties, true
+	case ".strings":
+		return Strings, true
+	}
+
+	return "", false
+}
+
+// Parse returns all keys of a locale file with their values. Keys of nested
+// structures, e.g. plural forms, are joined with dots.
+func Parse(format Format, data []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	switch format {
+	case JSON:
+		var content interface{}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return nil, err
+		}
+		flatten(values, "", content)
+	case YAML:
+		var content interface{}
+		if err := yaml.Unmarshal(data, &content); err != nil {
+			return nil, err
+		}
+		flatten(values, "", content)
+	case Properties:
+		// values such as "Hello ${name}" are kept as they are
+		loader := &properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+		props, err := loader.LoadBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range props.Keys() {
+			values[key], _ = props.Get(key)
+		}
+	case Strings:
+		return parseStrings(data)
+	default:
+		return nil, fmt.Errorf("cannot parse keys of format %q", format)
+	}
+
+	return values, nil
+}
+
+func flatten(values map[string]string, prefix string, content interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch c := content.(type) {
+	case map[string]interface{}:
+		for k, v := range c {
+			flatten(values, join(k), v)
+		}
+	case map[interface{}]interface{}:
+		for k, v := range c {
+			flatten(values, join(fmt.Sprint(k)), v)
+		}
+	case []interface{}:
+		for i, v := range c {
+			flatten(values, join(fmt.Sprint(i)), v)
+		}
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(c)
+	}
+}
+
+// Diff lists the keys that differ between two versions of a locale file.
+type Diff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Compare returns the keys which were added, removed or changed in to
+// compared to from, each sorted by name.
+func Compare(from, to map[string]string) Diff {
+	diff := Diff{}
+
+	for key, value := range to {
+		fromValue, ok := from[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case fromValue != value:
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+
+	for key := range from {
+		if _, ok := to[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// Empty returns true if no keys differ.
+func (diff Diff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
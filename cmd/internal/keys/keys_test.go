@@ -0,0 +1,126 @@
+package keys
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		format Format
+		data   string
+		expect map[string]string
+	}{
+		{
+			format: JSON,
+			data:   `{"hello": "Hello", "apples": {"one": "1 apple", "other": "%d apples"}, "list": ["a", "b"], "empty": null}`,
+			expect: map[string]string{
+				"hello":        "Hello",
+				"apples.one":   "1 apple",
+				"apples.other": "%d apples",
+				"list.0":       "a",
+				"list.1":       "b",
+				"empty":        "",
+			},
+		}, {
+			format: YAML,
+			data:   "en:\n  hello: Hello\n  nested:\n    count: 3\n",
+			expect: map[string]string{
+				"en.hello":        "Hello",
+				"en.nested.count": "3",
+			},
+		}, {
+			format: Properties,
+			data:   "# comment\nhello=Hello\nbye = Good bye\n",
+			expect: map[string]string{
+				"hello": "Hello",
+				"bye":   "Good bye",
+			},
+		}, {
+			format: Properties,
+			data:   "greeting=Hello ${name}\nself=${self}\n",
+			expect: map[string]string{
+				"greeting": "Hello ${name}",
+				"self":     "${self}",
+			},
+		}, {
+			format: Strings,
+			data:   "/* comment */\n\"hello\" = \"Hello \\\"World\\\"\";\n// line comment\n\"multi\"=\"a\\nb\";\nunquoted = \"value\";\n",
+			expect: map[string]string{
+				"hello":    `Hello "World"`,
+				"multi":    "a\nb",
+				"unquoted": "value",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Parse(test.format, []byte(test.data))
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.format, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expect) {
+			t.Errorf("%s: expected %v, got %v", test.format, test.expect, result)
+		}
+	}
+}
+
+func TestParse_stringsErrors(t *testing.T) {
+	tests := []string{
+		`"hello" = "Hello"`,
+		`"hello" "Hello";`,
+		`"hello = "Hello;`,
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(Strings, []byte(test)); err == nil {
+			t.Errorf("expected error for %q", test)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		fileFormat string
+		path       string
+		expect     Format
+		ok         bool
+	}{
+		{"simple_json", "en.txt", JSON, true},
+		{"nested_json", "", JSON, true},
+		{"yml_symfony2", "", YAML, true},
+		{"properties", "", Properties, true},
+		{"strings", "", Strings, true},
+		{"", "config/en.yaml", YAML, true},
+		{"xlsx", "en.xlsx", "", false},
+	}
+
+	for _, test := range tests {
+		format, ok := DetectFormat(test.fileFormat, test.path)
+		if format != test.expect || ok != test.ok {
+			t.Errorf("%q %q: expected %q %v, got %q %v", test.fileFormat, test.path, test.expect, test.ok, format, ok)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := map[string]string{"a": "1", "b": "2", "c": "3"}
+	to := map[string]string{"a": "1", "b": "changed", "d": "4"}
+
+	diff := Compare(from, to)
+	expect := Diff{
+		Added:   []string{"d"},
+		Removed: []string{"c"},
+		Changed: []string{"b"},
+	}
+
+	if !reflect.DeepEqual(diff, expect) {
+		t.Errorf("expected %v, got %v", expect, diff)
+	}
+
+	if !Compare(from, from).Empty() {
+		t.Errorf("expected no differences when comparing a map to itself")
+	}
+}
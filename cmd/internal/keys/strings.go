@@ -0,0 +1,203 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parseStrings parses an iOS/macOS .strings file consisting of
+// `"key" = "value";` pairs and C-style comments.
+func parseStrings(data []byte) (map[string]string, error) {
+	p := &stringsParser{input: []rune(decodeStrings(data))}
+	values := map[string]string{}
+
+	for {
+		p.skipSpaceAndComments()
+		if p.eof() {
+			return values, nil
+		}
+
+		key, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaceAndComments()
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+
+		p.skipSpaceAndComments()
+		value, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaceAndComments()
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+
+		values[key] = value
+	}
+}
+
+// decodeStrings returns the content of a .strings file, which may be UTF-16
+// encoded with a byte order mark.
+func decodeStrings(data []byte) string {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		order = binary.BigEndian
+	default:
+		return strings.TrimPrefix(string(data), "\ufeff")
+	}
+
+	data = data[2:]
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+type stringsParser struct {
+	input []rune
+	pos   int
+	line  int
+}
+
+func (p *stringsParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *stringsParser) peek(offset int) rune {
+	if p.pos+offset >= len(p.input) {
+		return utf8.RuneError
+	}
+	return p.input[p.pos+offset]
+}
+
+func (p *stringsParser) next() rune {
+	r := p.input[p.pos]
+	p.pos++
+	if r == '\n' {
+		p.line++
+	}
+	return r
+}
+
+func (p *stringsParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line+1, fmt.Sprintf(format, args...))
+}
+
+func (p *stringsParser) skipSpaceAndComments() {
+	for !p.eof() {
+		switch {
+		case strings.ContainsRune(" \t\r\n", p.peek(0)):
+			p.next()
+		case p.peek(0) == '/' && p.peek(1) == '/':
+			for !p.eof() && p.peek(0) != '\n' {
+				p.next()
+			}
+		case p.peek(0) == '/' && p.peek(1) == '*':
+			p.next()
+			p.next()
+			for !p.eof() && !(p.peek(0) == '*' && p.peek(1) == '/') {
+				p.next()
+			}
+			if !p.eof() {
+				p.next()
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *stringsParser) expect(r rune) error {
+	if p.eof() {
+		return p.errorf("expected %q, got end of file", r)
+	}
+	if got := p.next(); got != r {
+		return p.errorf("expected %q, got %q", r, got)
+	}
+	return nil
+}
+
+// token reads a quoted string or an unquoted word.
+func (p *stringsParser) token() (string, error) {
+	if p.eof() {
+		return "", p.errorf("unexpected end of file")
+	}
+
+	if p.peek(0) != '"' {
+		var word strings.Builder
+		for !p.eof() && !strings.ContainsRune(" \t\r\n=;", p.peek(0)) {
+			word.WriteRune(p.next())
+		}
+		if word.Len() == 0 {
+			return "", p.errorf("unexpected %q", p.peek(0))
+		}
+		return word.String(), nil
+	}
+
+	p.next()
+	var s strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		r := p.next()
+		switch r {
+		case '"':
+			return s.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			escaped, err := p.escape()
+			if err != nil {
+				return "", err
+			}
+			s.WriteString(escaped)
+		default:
+			s.WriteRune(r)
+		}
+	}
+}
+
+func (p *stringsParser) escape() (string, error) {
+	r := p.next()
+	switch r {
+	case 'n':
+		return "\n", nil
+	case 't':
+		return "\t", nil
+	case 'r':
+		return "\r", nil
+	case '0':
+		return "\x00", nil
+	case 'U', 'u':
+		hex := ""
+		for i := 0; i < 4 && !p.eof(); i++ {
+			hex += string(p.next())
+		}
+		code, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return "", p.errorf("invalid unicode escape \\%c%s", r, hex)
+		}
+		return string(rune(code)), nil
+	default:
+		return string(r), nil
+	}
+}
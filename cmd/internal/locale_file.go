@@ -12,6 +12,13 @@ type LocaleFiles []*LocaleFile
 type LocaleFile struct {
 	Path, Name, ID, Code, Tag, FileFormat string
 	ExistsRemote                          bool
+
+	// mappedLocale is the remote locale id, name or code the locale code or
+	// name in the path is mapped to by a locale mapping.
+	mappedLocale string
+	// derived holds the values of placeholders derived from the locale code,
+	// like <language> and <region>, found in the path.
+	derived map[string]string
 }
 
 func (localeFile *LocaleFile) RelPath() string {
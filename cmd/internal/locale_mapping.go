@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// localeMappingKey is the key of locale mappings in the config, globally, in
+// the push and pull sections and in sources and targets.
+const localeMappingKey = "locale_mapping"
+
+// LocaleMapping maps the locale codes or names used in local file paths to
+// the codes, names or ids of locales in Phrase.
+type LocaleMapping map[string]string
+
+// toRemote returns the remote locale the local value is mapped to.
+func (mapping LocaleMapping) toRemote(local string) (string, bool) {
+	if local == "" {
+		return "", false
+	}
+	remote, ok := mapping[local]
+	return remote, ok
+}
+
+// toLocal returns the local value mapped to the remote locale of localeFile
+// and the field of the locale it is mapped to, "code", "name" or "id".
+// Mappings of its code are preferred over the ones of its name and id.
+func (mapping LocaleMapping) toLocal(localeFile *LocaleFile) (local string, field string, ok bool) {
+	locals := make([]string, 0, len(mapping))
+	for local := range mapping {
+		locals = append(locals, local)
+	}
+	sort.Strings(locals)
+
+	remotes := []struct{ field, value string }{
+		{"code", localeFile.Code},
+		{"name", localeFile.Name},
+		{"id", localeFile.ID},
+	}
+	for _, remote := range remotes {
+		if remote.value == "" {
+			continue
+		}
+		for _, local := range locals {
+			if mapping[local] == remote.value {
+				return local, remote.field, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// merge returns the mappings of mapping overridden by the ones of other.
+// Mappings of mapping to a remote locale other maps to are dropped, so that
+// other also takes precedence when mapping back.
+func (mapping LocaleMapping) merge(other LocaleMapping) LocaleMapping {
+	overridden := map[string]bool{}
+	for _, remote := range other {
+		overridden[remote] = true
+	}
+
+	merged := LocaleMapping{}
+	for local, remote := range mapping {
+		if !overridden[remote] {
+			merged[local] = remote
+		}
+	}
+	for local, remote := range other {
+		merged[local] = remote
+	}
+	return merged
+}
+
+// sectionLocaleMapping reads the locale mapping of the push or pull section
+// raw. It is read separately, as the config reader lowercases the keys of
+// maps in sections, which would break mapping back to local names.
+func sectionLocaleMapping(raw []byte) (LocaleMapping, error) {
+	var section struct {
+		LocaleMapping LocaleMapping `yaml:"locale_mapping"`
+	}
+	if err := yaml.Unmarshal(raw, &section); err != nil {
+		return nil, err
+	}
+	return section.LocaleMapping, nil
+}
+
+// moveLocaleMapping moves the global locale mapping of the phrase section of
+// a config file into its push and pull sections, where mappings of the
+// sections take precedence.
+func moveLocaleMapping(section map[interface{}]interface{}) error {
+	raw, found := section[localeMappingKey]
+	if !found {
+		return nil
+	}
+	delete(section, localeMappingKey)
+
+	global, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("%s must map local locale codes or names to locales in Phrase", localeMappingKey)
+	}
+
+	for _, name := range []string{"push", "pull"} {
+		sub, ok := section[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		// like LocaleMapping.merge, without converting the keys, which
+		// sectionLocaleMapping does later
+		mapping, _ := sub[localeMappingKey].(map[interface{}]interface{})
+		overridden := map[interface{}]bool{}
+		for _, remote := range mapping {
+			overridden[remote] = true
+		}
+
+		merged := map[interface{}]interface{}{}
+		for local, remote := range global {
+			if !overridden[remote] {
+				merged[local] = remote
+			}
+		}
+		for local, remote := range mapping {
+			merged[local] = remote
+		}
+		sub[localeMappingKey] = merged
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTarget_ReplacePlaceholders_localeMapping(t *testing.T) {
+	localeFile := &LocaleFile{ID: "l-ptbr", Name: "Portuguese", Code: "pt-BR"}
+
+	tests := []struct {
+		mapping  LocaleMapping
+		expected string
+	}{
+		{nil, "pt-BR/Portuguese.json"},
+		{LocaleMapping{"pt_BR": "pt-BR"}, "pt_BR/Portuguese.json"},
+		{LocaleMapping{"portugues": "Portuguese"}, "pt-BR/portugues.json"},
+		{LocaleMapping{"pt": "l-ptbr"}, "pt/pt.json"},
+		{LocaleMapping{"pt_BR": "pt-BR", "portugues": "Portuguese"}, "pt_BR/Portuguese.json"},
+		{LocaleMapping{"de_DE": "de-DE"}, "pt-BR/Portuguese.json"},
+	}
+
+	for _, test := range tests {
+		target := &Target{File: "<locale_code>/<locale_name>.json", LocaleMapping: test.mapping}
+
+		path, err := target.ReplacePlaceholders(localeFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, _ := filepath.Abs(test.expected)
+		if path != expected {
+			t.Errorf("%v: expected %s, got %s", test.mapping, expected, path)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phrase/phrase-go"
+)
+
+// localeMatchProblem explains why a locale file could not be matched to a
+// single remote locale.
+type localeMatchProblem struct {
+	localeFile *LocaleFile
+	message    string
+	candidates []*phrase.Locale
+}
+
+// LocaleMatchError is returned in strict mode if locale files match several
+// remote locales, or only match by part of a locale name.
+type LocaleMatchError struct {
+	problems []*localeMatchProblem
+}
+
+func (err *LocaleMatchError) Error() string {
+	lines := []string{fmt.Sprintf("%d locale file(s) could not be matched to a single locale in Phrase:", len(err.problems))}
+	for _, problem := range err.problems {
+		line := fmt.Sprintf("  %s %s", problem.localeFile.RelPath(), problem.message)
+		if len(problem.candidates) > 0 {
+			line += ": " + describeLocales(problem.candidates)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "Map the files to locales with locale_mapping in the source, or set strict_locales: false in the push section to use the first match.")
+	return strings.Join(lines, "\n")
+}
+
+// add appends the problems of other.
+func (err *LocaleMatchError) add(other *LocaleMatchError) {
+	err.problems = append(err.problems, other.problems...)
+}
+
+func describeLocales(locales []*phrase.Locale) string {
+	descriptions := make([]string, len(locales))
+	for i, locale := range locales {
+		descriptions[i] = fmt.Sprintf("%s (code %s, id %s)", locale.Name, locale.Code, locale.Id)
+	}
+	return strings.Join(descriptions, ", ")
+}
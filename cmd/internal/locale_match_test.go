@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antihax/optional"
+	"github.com/phrase/phrase-go"
+)
+
+func TestSource_getRemoteLocaleForLocaleFile(t *testing.T) {
+	remoteLocales := []*phrase.Locale{
+		{Id: "l-en", Name: "English", Code: "en"},
+		{Id: "l-de", Name: "German", Code: "de"},
+		{Id: "l-deat", Name: "German (Austria)", Code: "de-AT"},
+		{Id: "l-ptbr", Name: "pt-BR informal (Brazil)", Code: "pt-BR"},
+		{Id: "l-ptpt", Name: "Portuguese (Portugal)", Code: "pt-PT"},
+	}
+
+	tests := []struct {
+		name       string
+		strict     bool
+		localeID   string
+		localeFile LocaleFile
+		expected   string
+		problem    string
+		candidates []string
+	}{
+		{
+			name:       "exact code",
+			strict:     true,
+			localeFile: LocaleFile{Code: "de"},
+			expected:   "l-de",
+		}, {
+			name:       "exact name",
+			strict:     true,
+			localeFile: LocaleFile{Name: "German (Austria)"},
+			expected:   "l-deat",
+		}, {
+			name:       "language preferring the exact code",
+			strict:     true,
+			localeFile: LocaleFile{Code: "de", derived: map[string]string{"language": "de"}},
+			expected:   "l-de",
+		}, {
+			name:       "ambiguous language in strict mode",
+			strict:     true,
+			localeFile: LocaleFile{Code: "pt", derived: map[string]string{"language": "pt"}},
+			problem:    "matches several locales",
+			candidates: []string{"l-ptbr", "l-ptpt"},
+		}, {
+			name:       "ambiguous language uses the first match",
+			localeFile: LocaleFile{Code: "pt", derived: map[string]string{"language": "pt"}},
+			expected:   "l-ptbr",
+		}, {
+			name:       "partial name in strict mode",
+			strict:     true,
+			localeID:   "<locale_code> informal",
+			localeFile: LocaleFile{Code: "pt-BR"},
+			problem:    `only matches locales whose names contain "pt-BR informal"`,
+			candidates: []string{"l-ptbr"},
+		}, {
+			name:       "partial name",
+			localeID:   "<locale_code> informal",
+			localeFile: LocaleFile{Code: "pt-BR"},
+			expected:   "l-ptbr",
+		}, {
+			name:       "locale_id by code",
+			strict:     true,
+			localeID:   "en",
+			localeFile: LocaleFile{},
+			expected:   "l-en",
+		}, {
+			name:       "locale_id by name",
+			strict:     true,
+			localeID:   "German",
+			localeFile: LocaleFile{},
+			expected:   "l-de",
+		}, {
+			name:       "params without locale_id",
+			strict:     true,
+			localeID:   "unset",
+			localeFile: LocaleFile{Code: "en"},
+			expected:   "l-en",
+		}, {
+			name:       "mapped locale",
+			strict:     true,
+			localeFile: LocaleFile{Code: "l-en", mappedLocale: "l-en"},
+			expected:   "l-en",
+		}, {
+			name:       "mapped to a missing locale",
+			strict:     true,
+			localeFile: LocaleFile{Code: "fr", mappedLocale: "fr"},
+			problem:    `is mapped to "fr" by locale_mapping, which is not a locale in Phrase`,
+		}, {
+			name:       "no match",
+			strict:     true,
+			localeFile: LocaleFile{Code: "fr"},
+		}, {
+			name:       "no locale information",
+			strict:     true,
+			localeFile: LocaleFile{},
+		},
+	}
+
+	for _, test := range tests {
+		source := &Source{RemoteLocales: remoteLocales, strictLocales: test.strict}
+		switch test.localeID {
+		case "":
+		case "unset":
+			source.Params = &phrase.UploadCreateOpts{}
+		default:
+			source.Params = &phrase.UploadCreateOpts{LocaleId: optional.NewString(test.localeID)}
+		}
+
+		localeFile := test.localeFile
+		locale, problem := source.getRemoteLocaleForLocaleFile(&localeFile)
+
+		if test.problem != "" {
+			if problem == nil {
+				t.Errorf("%s: expected problem %q, got locale %v", test.name, test.problem, locale)
+				continue
+			}
+			if problem.message != test.problem {
+				t.Errorf("%s: expected problem %q, got %q", test.name, test.problem, problem.message)
+			}
+			ids := []string{}
+			for _, candidate := range problem.candidates {
+				ids = append(ids, candidate.Id)
+			}
+			if strings.Join(ids, ",") != strings.Join(test.candidates, ",") {
+				t.Errorf("%s: expected candidates %v, got %v", test.name, test.candidates, ids)
+			}
+			continue
+		}
+
+		if problem != nil {
+			t.Errorf("%s: unexpected problem %q", test.name, problem.message)
+			continue
+		}
+		id := ""
+		if locale != nil {
+			id = locale.Id
+		}
+		if id != test.expected {
+			t.Errorf("%s: expected locale %q, got %q", test.name, test.expected, id)
+		}
+	}
+}
+
+func TestLocaleMatchError_listsCandidates(t *testing.T) {
+	source := &Source{
+		RemoteLocales: []*phrase.Locale{
+			{Id: "l-ptbr", Name: "Portuguese (Brazil)", Code: "pt-BR"},
+			{Id: "l-ptpt", Name: "Portuguese (Portugal)", Code: "pt-PT"},
+		},
+		strictLocales: true,
+	}
+
+	_, problem := source.getRemoteLocaleForLocaleFile(&LocaleFile{Code: "pt", derived: map[string]string{"language": "pt"}})
+	if problem == nil {
+		t.Fatal("expected a problem")
+	}
+
+	err := &LocaleMatchError{problems: []*localeMatchProblem{problem}}
+	expected := "matches several locales: Portuguese (Brazil) (code pt-BR, id l-ptbr), Portuguese (Portugal) (code pt-PT, id l-ptpt)"
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error to contain %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LockFileName is the name of the file in the working directory recording
+// the state of the last pull and push.
+const LockFileName = ".phrase.lock"
+
+// LockFile records what was downloaded to every target path and uploaded
+// from every source path, so that files whose content did not change are
+// neither written nor uploaded again.
+type LockFile struct {
+	Version   int                       `json:"version"`
+	Downloads map[string]*DownloadState `json:"downloads,omitempty"`
+	Uploads   map[string][]*UploadState `json:"uploads,omitempty"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// DownloadState is the state of a single file after it was pulled.
+type DownloadState struct {
+	LocaleID string `json:"locale_id"`
+	Branch   string `json:"branch,omitempty"`
+	// Params is a hash of the download parameters.
+	Params string `json:"params"`
+	SHA256 string `json:"sha256"`
+	ETag   string `json:"etag,omitempty"`
+}
+
+// UploadState is the state of a single file after it was last pushed to a
+// project and branch.
+type UploadState struct {
+	ProjectID string `json:"project_id"`
+	Branch    string `json:"branch,omitempty"`
+	// Params is a hash of the upload parameters.
+	Params     string    `json:"params"`
+	SHA256     string    `json:"sha256"`
+	UploadID   string    `json:"upload_id"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	// Unconfirmed is set if push did not wait for the upload to be
+	// processed, so it is unknown whether it succeeded.
+	Unconfirmed bool `json:"unconfirmed,omitempty"`
+}
+
+// LoadLockFile reads the lock file of the working directory. A missing file
+// results in an empty lock file.
+func LoadLockFile() (*LockFile, error) {
+	lock := &LockFile{
+		Version:   1,
+		Downloads: map[string]*DownloadState{},
+		Uploads:   map[string][]*UploadState{},
+		path:      LockFileName,
+	}
+
+	data, err := ioutil.ReadFile(lock.path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("could not read %s: %s", lock.path, err)
+	}
+
+	if lock.Downloads == nil {
+		lock.Downloads = map[string]*DownloadState{}
+	}
+	if lock.Uploads == nil {
+		lock.Uploads = map[string][]*UploadState{}
+	}
+
+	return lock, nil
+}
+
+// Save writes the lock file.
+func (lock *LockFile) Save() error {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(lock.path, append(data, '\n'), DefaultFileMode)
+}
+
+func (lock *LockFile) download(path string) *DownloadState {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+
+	return lock.Downloads[lockFileKey(path)]
+}
+
+func (lock *LockFile) setDownload(path string, state *DownloadState) {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+
+	lock.Downloads[lockFileKey(path)] = state
+}
+
+func (lock *LockFile) upload(path, projectID, branch string) *UploadState {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+
+	for _, state := range lock.Uploads[lockFileKey(path)] {
+		if state.ProjectID == projectID && state.Branch == branch {
+			return state
+		}
+	}
+	return nil
+}
+
+// setUpload replaces the state of path for the project and branch of state.
+func (lock *LockFile) setUpload(path string, state *UploadState) {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+
+	key := lockFileKey(path)
+	states := []*UploadState{state}
+	for _, previous := range lock.Uploads[key] {
+		if previous.ProjectID != state.ProjectID || previous.Branch != state.Branch {
+			states = append(states, previous)
+		}
+	}
+	lock.Uploads[key] = states
+}
+
+// lockFileKey returns path relative to the working directory, so that the
+// lock file can be committed.
+func lockFileKey(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileSHA256 returns the hash of the file at path, or an empty string if it
+// cannot be read.
+func fileSHA256(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return sha256Hex(data)
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antihax/optional"
+	"github.com/phrase/phrase-go"
+)
+
+// inTempDir changes the working directory to a new temporary directory for
+// the rest of the test and returns it.
+func inTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "test-lock-file_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, _ = filepath.EvalSymlinks(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func TestLockFile_uploadsPerProjectAndBranch(t *testing.T) {
+	dir := inTempDir(t)
+	path := filepath.Join(dir, "locales", "en.json")
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock.setUpload(path, &UploadState{ProjectID: "p1", UploadID: "u1"})
+	lock.setUpload(path, &UploadState{ProjectID: "p1", Branch: "feature", UploadID: "u2"})
+	lock.setUpload(path, &UploadState{ProjectID: "p2", UploadID: "u3"})
+	lock.setUpload(path, &UploadState{ProjectID: "p1", UploadID: "u4"})
+
+	if err := lock.Save(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if states := lock.Uploads["locales/en.json"]; len(states) != 3 {
+		t.Errorf("expected 3 uploads keyed by the relative path, got %v", lock.Uploads)
+	}
+
+	tests := []struct {
+		projectID, branch, uploadID string
+	}{
+		{"p1", "", "u4"},
+		{"p1", "feature", "u2"},
+		{"p2", "", "u3"},
+		{"p2", "feature", ""},
+	}
+	for _, test := range tests {
+		uploadID := ""
+		if state := lock.upload(path, test.projectID, test.branch); state != nil {
+			uploadID = state.UploadID
+		}
+		if uploadID != test.uploadID {
+			t.Errorf("%s/%s: expected upload %q, got %q", test.projectID, test.branch, test.uploadID, uploadID)
+		}
+	}
+}
+
+func TestSource_checkUnchanged(t *testing.T) {
+	dir := inTempDir(t)
+	path := filepath.Join(dir, "en.json")
+	if err := ioutil.WriteFile(path, []byte(`{"hello": "Hello"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newSource := func() *Source {
+		return &Source{
+			ProjectID: "p1",
+			Params:    &phrase.UploadCreateOpts{FileFormat: optional.NewString("simple_json")},
+		}
+	}
+	localeFile := &LocaleFile{Path: path, Code: "en", ExistsRemote: true}
+
+	lock, _ := LoadLockFile()
+	cmd := &PushCommand{lock: lock}
+	source := newSource()
+	source.recordPush([]*pushResult{{
+		localeFile: localeFile,
+		upload:     &phrase.Upload{Id: "u1", State: "success"},
+		waited:     true,
+		sha256:     fileSHA256(path),
+	}}, cmd)
+
+	unchanged := func(source *Source, cmd *PushCommand) bool {
+		result := &pushResult{localeFile: localeFile}
+		source.checkUnchanged(result, cmd)
+		return result.unchanged
+	}
+
+	if !unchanged(source, cmd) {
+		t.Errorf("expected the recorded file to be unchanged")
+	}
+	if unchanged(source, &PushCommand{lock: lock, Force: true}) {
+		t.Errorf("expected --force to upload the file again")
+	}
+	if unchanged(source, &PushCommand{lock: lock, Branch: "feature"}) {
+		t.Errorf("expected the file to be changed on another branch")
+	}
+	if unchanged(source, &PushCommand{lock: lock, Tag: "release"}) {
+		t.Errorf("expected the file to be changed with another tag")
+	}
+
+	other := newSource()
+	other.Params.UpdateTranslations = optional.NewBool(true)
+	if unchanged(other, cmd) {
+		t.Errorf("expected the file to be changed with other params")
+	}
+
+	other = newSource()
+	other.ProjectID = "p2"
+	if unchanged(other, cmd) {
+		t.Errorf("expected the file to be changed in another project")
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"hello": "Hi"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if unchanged(source, cmd) {
+		t.Errorf("expected the file to be changed with other content")
+	}
+}
+
+func TestSource_checkUnchanged_unconfirmed(t *testing.T) {
+	routes := map[string]string{
+		"GET /projects/p1/uploads/u1": `{"id": "u1", "state": "processing"}`,
+	}
+	testAPI(t, routes)
+
+	dir := inTempDir(t)
+	path := filepath.Join(dir, "en.json")
+	if err := ioutil.WriteFile(path, []byte(`{"hello": "Hello"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &Source{ProjectID: "p1", Params: &phrase.UploadCreateOpts{}}
+	localeFile := &LocaleFile{Path: path, Code: "en", ExistsRemote: true}
+
+	lock, _ := LoadLockFile()
+	cmd := &PushCommand{lock: lock}
+	source.recordPush([]*pushResult{{
+		localeFile: localeFile,
+		upload:     &phrase.Upload{Id: "u1", State: "enqueued"},
+		sha256:     fileSHA256(path),
+	}}, cmd)
+
+	if state := lock.upload(path, "p1", ""); state == nil || !state.Unconfirmed {
+		t.Fatalf("expected an upload which was not waited for to be unconfirmed, got %+v", state)
+	}
+
+	result := &pushResult{localeFile: localeFile}
+	source.checkUnchanged(result, cmd)
+	if result.unchanged {
+		t.Errorf("expected a file whose upload is still processing to be uploaded again")
+	}
+	if !lock.upload(path, "p1", "").Unconfirmed {
+		t.Errorf("expected the upload to stay unconfirmed while it is processing")
+	}
+
+	routes["GET /projects/p1/uploads/u1"] = `{"id": "u1", "state": "success"}`
+	result = &pushResult{localeFile: localeFile}
+	source.checkUnchanged(result, cmd)
+	if !result.unchanged {
+		t.Errorf("expected a file whose upload succeeded to be unchanged")
+	}
+	if lock.upload(path, "p1", "").Unconfirmed {
+		t.Errorf("expected the successful upload to be confirmed")
+	}
+}
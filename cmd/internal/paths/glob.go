@@ -4,110 +4,364 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var dirGlobOperator = "**"
 
 // dirGlobOperatorUseValid returns false if '**' occurs, but '/**/' doesn't and pattern does not start with '**/'.
 func dirGlobOperatorUseValid(pattern string) bool {
-	containsOperator := strings.Contains(pattern, dirGlobOperator)
-	operatorIsOwnPathSegment := strings.Contains(pattern, string(filepath.Separator)+dirGlobOperator+string(filepath.Separator))
-	startsWithOperator := strings.HasPrefix(pattern, dirGlobOperator+string(filepath.Separator))
+	for i := strings.Index(pattern, dirGlobOperator); i >= 0; {
+		start := i == 0 || pattern[i-1] == '/'
+		end := i+2 == len(pattern) || pattern[i+2] == '/'
+		if !start || !end {
+			return false
+		}
 
-	return !containsOperator || (operatorIsOwnPathSegment || startsWithOperator)
+		next := strings.Index(pattern[i+2:], dirGlobOperator)
+		if next < 0 {
+			break
+		}
+		i += 2 + next
+	}
+	return true
 }
 
-// SplitAtDirGlobOperator splits pattern at the '**' operator, if it's contained, then splits path accordingly,
-// dropping the segments that the '**' operator would have matched against. The returned paths will match the returned patterns.
-// An error is returned if the '**' operator is incorrectly used in pattern.
-func SplitAtDirGlobOperator(path, pattern string) (pathStart, patternStart, pathEnd, patternEnd string, err error) {
-	if !dirGlobOperatorUseValid(pattern) {
-		return "", "", "", "", fmt.Errorf("invalid pattern '%s': the ** globbing operator may only be used as path segment on its own, i.e. …/**/… or **/…", pattern)
+// Glob supports * and ** globbing according to https://help.phrase.com/phraseapp-for-developers/phraseapp-client/configuration#globbing.
+// Additionally ** may be used several times, {a,b} matches one of the comma
+// separated alternatives and [a-z] or [!a-z] match a single character of a
+// class. ? and unclosed brackets match themselves. Files matching one of
+// excludes, or inside a directory matching one, are left out. Excludes without
+// a slash match files and directories of that name at any depth.
+func Glob(pattern string, excludes ...string) (matches []string, err error) {
+	return GlobIgnoring(pattern, nil, excludes...)
+}
+
+// GlobIgnoring works like Glob, but also leaves out the files ignored by
+// ignore, which may be nil.
+func GlobIgnoring(pattern string, ignore *Ignore, excludes ...string) (matches []string, err error) {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeREs := make([]*regexp.Regexp, len(excludes))
+	for i, exclude := range excludes {
+		if excludeREs[i], err = compileExclude(exclude); err != nil {
+			return nil, err
+		}
 	}
 
-	parts := strings.Split(pattern, string(filepath.Separator)+dirGlobOperator+string(filepath.Separator))
-	patternStart = parts[0]
-	if len(parts) == 2 {
-		patternEnd = parts[1]
+	g := &globber{pattern: re, excludes: excludeREs, ignore: ignore, matches: []string{}}
+
+	base, depth := walkRoot(pattern)
+	if depth == 0 {
+		// pattern is a plain path
+		if IsDir(filepath.FromSlash(pattern)) || g.excluded(pattern, false) || ignore.IgnoredWithParents(pattern, false) {
+			return g.matches, nil
+		}
+		return filesOnly([]string{filepath.FromSlash(pattern)}), nil
 	}
 
-	numSegmentsStart := len(Segments(patternStart))
-	numSegmentsEnd := len(Segments(patternEnd))
+	if base != "." && ignore.IgnoredWithParents(base, true) {
+		return g.matches, nil
+	}
 
-	segments := Segments(path)
+	// without ** the depth is limited, so that symlinked directories can be
+	// followed without the risk of cycles
+	g.followSymlinks = depth > 0
+	g.walk(base, depth)
 
-	pathStart = filepath.Join(segments[:numSegmentsStart]...)
-	pathEnd = filepath.Join(segments[len(segments)-numSegmentsEnd:]...)
+	return g.matches, nil
+}
 
-	if strings.HasPrefix(path, "/") && pathStart != "" {
-		pathStart = "/" + pathStart
+// Match returns true if path matches the glob pattern as used by Glob.
+func Match(pattern, path string) (bool, error) {
+	re, err := compile(filepath.ToSlash(filepath.Clean(pattern)))
+	if err != nil {
+		return false, err
 	}
+	return re.MatchString(filepath.ToSlash(filepath.Clean(path))), nil
+}
 
-	return
+// ValidatePattern returns an error if pattern is not a valid glob pattern.
+func ValidatePattern(pattern string) error {
+	_, err := compile(filepath.ToSlash(filepath.Clean(pattern)))
+	return err
 }
 
-// Glob supports * and ** globbing according to https://help.phrase.com/phraseapp-for-developers/phraseapp-client/configuration#globbing
-func Glob(pattern string) (matches []string, err error) {
-	pattern = filepath.Clean(pattern)
-	pattern = escape(pattern)
+// PatternToRegexp translates the glob pattern to a regular expression matching
+// the same paths with slashes as separators. It is not anchored.
+func PatternToRegexp(pattern string) (string, error) {
+	pattern = filepath.ToSlash(pattern)
+	if !dirGlobOperatorUseValid(pattern) {
+		return "", fmt.Errorf("invalid pattern '%s': the ** globbing operator may only be used as path segment on its own, i.e. …/**/… or **/…", pattern)
+	}
+	return translate(pattern, false), nil
+}
 
-	if strings.Count(pattern, dirGlobOperator) > 1 {
-		return nil, fmt.Errorf("invalid pattern '%s': the ** globbing operator may only be used once in a pattern", pattern)
+func compile(pattern string) (*regexp.Regexp, error) {
+	expr, err := PatternToRegexp(pattern)
+	if err != nil {
+		return nil, err
 	}
 
-	if !dirGlobOperatorUseValid(pattern) {
-		return nil, fmt.Errorf("invalid pattern '%s': the ** globbing operator may only be used as path segment on its own, i.e. …/**/… or **/…", pattern)
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
 	}
+	return re, nil
+}
 
-	if strings.Contains(pattern, dirGlobOperator) {
-		parts := strings.Split(pattern, dirGlobOperator)
-		basePattern, endPattern := filepath.Clean(parts[0]), filepath.Clean(parts[1])
+func compileExclude(exclude string) (*regexp.Regexp, error) {
+	exclude = filepath.ToSlash(filepath.Clean(exclude))
+	if !strings.Contains(exclude, "/") {
+		exclude = dirGlobOperator + "/" + exclude
+	}
+	return compile(exclude)
+}
 
-		baseCandidates, err := filepath.Glob(basePattern)
-		if err != nil {
-			return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+// translate translates a pattern with slashes as separators to a regular
+// expression. anyChar makes ? match a single character like in ignore files.
+func translate(pattern string, anyChar bool) string {
+	var expr strings.Builder
+	for i := 0; i < len(pattern); {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			r, size := utf8.DecodeRuneInString(pattern[i+1:])
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			i += 1 + size
+		case pattern[i:] == "/"+dirGlobOperator:
+			// matches the directory itself and everything inside of it
+			expr.WriteString("(?:/.*)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], dirGlobOperator+"/"):
+			expr.WriteString("(?:.*/)?")
+			i += 3
+		case pattern[i:] == dirGlobOperator:
+			expr.WriteString(".*")
+			i += 2
+		case c == '*':
+			expr.WriteString("[^/]*")
+			i++
+		case c == '?' && anyChar:
+			expr.WriteString("[^/]")
+			i++
+		case c == '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				expr.WriteString(`\[`)
+				i++
+				continue
+			}
+			expr.WriteString(translateClass(pattern[i+1 : end]))
+			i = end + 1
+		case c == '{':
+			alternatives, end := braceAlternatives(pattern, i)
+			if end < 0 {
+				expr.WriteString(`\{`)
+				i++
+				continue
+			}
+			for j, alternative := range alternatives {
+				alternatives[j] = translate(alternative, anyChar)
+			}
+			expr.WriteString("(?:" + strings.Join(alternatives, "|") + ")")
+			i = end + 1
+		default:
+			r, size := utf8.DecodeRuneInString(pattern[i:])
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			i += size
 		}
+	}
+	return expr.String()
+}
 
-		for _, base := range directoriesOnly(baseCandidates) {
-			_ = filepath.Walk(filepath.Clean(base), func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return err
-				}
+// classEnd returns the index of the bracket closing the character class
+// starting at i, or -1 if it isn't closed within the path segment.
+func classEnd(pattern string, i int) int {
+	j := i + 1
+	if j < len(pattern) && (pattern[j] == '!' || pattern[j] == '^') {
+		j++
+	}
+	if j < len(pattern) && pattern[j] == ']' {
+		j++
+	}
+	for ; j < len(pattern); j++ {
+		switch pattern[j] {
+		case '/':
+			return -1
+		case '\\':
+			j++
+		case ']':
+			return j
+		}
+	}
+	return -1
+}
 
-				if !info.IsDir() {
-					return nil
-				}
+func translateClass(class string) string {
+	var expr strings.Builder
+	expr.WriteString("[")
+	if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+		// a negated class must not match the separator either
+		expr.WriteString("^/")
+		class = class[1:]
+	}
+	for i := 0; i < len(class); {
+		r, size := utf8.DecodeRuneInString(class[i:])
+		if r == '\\' && i+size < len(class) {
+			i += size
+			r, size = utf8.DecodeRuneInString(class[i:])
+		}
+		if r != '-' && strings.ContainsRune(`\[]^`, r) {
+			expr.WriteRune('\\')
+		}
+		expr.WriteRune(r)
+		i += size
+	}
+	expr.WriteString("]")
+	return expr.String()
+}
 
-				matchesInBase, err := Glob(filepath.Join(path, endPattern))
-				if err != nil {
-					return err
+// braceAlternatives returns the comma separated alternatives of the braces
+// starting at i and the index of the closing brace. The index is -1 if the
+// braces are not closed or contain no comma.
+func braceAlternatives(pattern string, i int) ([]string, int) {
+	alternatives := []string{}
+	depth := 0
+	start := i + 1
+	for j := i; j < len(pattern); j++ {
+		switch pattern[j] {
+		case '\\':
+			j++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				if len(alternatives) == 0 {
+					return nil, -1
 				}
+				return append(alternatives, pattern[start:j]), j
+			}
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[start:j])
+				start = j + 1
+			}
+		}
+	}
+	return nil, -1
+}
+
+// WalkRoot returns the directory the files matching the glob pattern are in
+// and the depth of the files below it, as Glob searches them. The depth is -1
+// if it is not limited and 0 if pattern contains no wildcards, in which case
+// the directory is pattern itself.
+func WalkRoot(pattern string) (string, int) {
+	base, depth := walkRoot(filepath.ToSlash(filepath.Clean(pattern)))
+	return filepath.FromSlash(base), depth
+}
+
+// walkRoot returns the directory the files matching pattern are in and the
+// depth of the files below it. The depth is -1 if it is not limited and 0
+// if pattern contains no wildcards.
+func walkRoot(pattern string) (string, int) {
+	segments := strings.Split(pattern, "/")
+
+	static := 0
+	for static < len(segments) && !strings.ContainsAny(segments[static], `*[{\`) {
+		static++
+	}
+	if static == len(segments) {
+		return pattern, 0
+	}
+
+	base := strings.Join(segments[:static], "/")
+	switch {
+	case static == 0:
+		base = "."
+	case base == "":
+		base = "/"
+	}
+
+	if strings.Contains(pattern, dirGlobOperator) || strings.Contains(pattern, "{") {
+		return base, -1
+	}
+	return base, len(segments) - static
+}
+
+type globber struct {
+	pattern        *regexp.Regexp
+	excludes       []*regexp.Regexp
+	ignore         *Ignore
+	followSymlinks bool
+	matches        []string
+}
+
+// walk adds the files matching the pattern in dir and, up to depth levels,
+// its subdirectories.
+func (g *globber) walk(dir string, depth int) {
+	entries, err := os.ReadDir(filepath.FromSlash(dir))
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := joinSlash(dir, entry.Name())
 
-				matches = append(matches, matchesInBase...)
-				return nil
-			})
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.FromSlash(path))
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+			if isDir && !g.followSymlinks {
+				continue
+			}
 		}
-	} else {
-		candidates, err := filepath.Glob(pattern)
-		if err != nil {
-			return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+
+		if g.excluded(path, isDir) {
+			continue
+		}
+
+		if isDir {
+			if depth != 1 {
+				g.walk(path, depth-1)
+			}
+			continue
 		}
-		matches = filesOnly(candidates)
-	}
 
-	return matches, nil
+		if g.pattern.MatchString(path) {
+			g.matches = append(g.matches, filepath.FromSlash(path))
+		}
+	}
 }
 
-// escape escapes characters which filepath.Glob would otherwise handle in a special way (except on Windows...)
-func escape(s string) string {
-	if runtime.GOOS == "windows" {
-		return s
+func (g *globber) excluded(path string, isDir bool) bool {
+	for _, exclude := range g.excludes {
+		if exclude.MatchString(path) {
+			return true
+		}
 	}
+	return g.ignore.Ignored(path, isDir)
+}
 
-	s = strings.Replace(s, "?", "\\?", -1)
-	return strings.Replace(s, "[", "\\[", -1)
+func joinSlash(dir, name string) string {
+	switch {
+	case dir == ".":
+		return name
+	case strings.HasSuffix(dir, "/"):
+		return dir + name
+	default:
+		return dir + "/" + name
+	}
 }
 
 func filter(candidates []string, f func(os.FileInfo) bool) []string {
@@ -131,9 +385,3 @@ func filesOnly(candidates []string) []string {
 		return !fi.IsDir()
 	})
 }
-
-func directoriesOnly(candidates []string) []string {
-	return filter(candidates, func(fi os.FileInfo) bool {
-		return fi.IsDir()
-	})
-}
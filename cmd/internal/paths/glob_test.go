@@ -78,7 +78,9 @@ func TestGlob_specialCharacters(t *testing.T) {
 			"foo?bar/?",
 			"foo?bar/_[^x]_.yml",
 		},
-		"bar[a*/_[^x*]_.yml": {
+		// [^x*] is a character class, which matches a single character
+		"bar[a*/_[^x*]_.yml": {},
+		"bar[a*/_\\[^x*]_.yml": {
 			"bar[a-z]/_[^x]_.yml",
 		},
 		"bla/\\*/*": {
@@ -97,6 +99,165 @@ func TestGlob_specialCharacters(t *testing.T) {
 	testGlob(directories, files, tests, t)
 }
 
+func TestGlob_extended(t *testing.T) {
+	directories := []string{
+		"packages/app/src/i18n",
+		"packages/app/src/components/i18n",
+		"packages/app/node_modules/lib/src/i18n",
+		"packages/lib/src/i18n",
+		"dist/i18n",
+	}
+
+	files := []string{
+		"en.json",
+		"de.json",
+		"pt-BR.json",
+	}
+
+	tests := map[string][]string{
+		"packages/*/src/**/i18n/*.json": {
+			"packages/app/src/i18n/en.json",
+			"packages/app/src/i18n/de.json",
+			"packages/app/src/i18n/pt-BR.json",
+			"packages/app/src/components/i18n/en.json",
+			"packages/app/src/components/i18n/de.json",
+			"packages/app/src/components/i18n/pt-BR.json",
+			"packages/lib/src/i18n/en.json",
+			"packages/lib/src/i18n/de.json",
+			"packages/lib/src/i18n/pt-BR.json",
+		},
+		"**/lib/**/{en,de}.json": {
+			"packages/app/node_modules/lib/src/i18n/en.json",
+			"packages/app/node_modules/lib/src/i18n/de.json",
+			"packages/lib/src/i18n/en.json",
+			"packages/lib/src/i18n/de.json",
+		},
+		"{dist,packages/lib/src}/i18n/[a-e]?.json": {},
+		"{dist,packages/lib/src}/i18n/[a-e]*.json": {
+			"dist/i18n/en.json",
+			"dist/i18n/de.json",
+			"packages/lib/src/i18n/en.json",
+			"packages/lib/src/i18n/de.json",
+		},
+		"dist/i18n/[!a-e]*.json": {
+			"dist/i18n/pt-BR.json",
+		},
+	}
+
+	testGlob(directories, files, tests, t)
+}
+
+func TestGlob_excludes(t *testing.T) {
+	base, err := ioutil.TempDir("", "test-glob_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	for _, dir := range []string{"src/i18n", "node_modules/lib/i18n", "src/fixtures/i18n"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(base, dir, "en.json"), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		excludes []string
+		expected []string
+	}{
+		{
+			excludes: []string{"node_modules"},
+			expected: []string{"src/i18n/en.json", "src/fixtures/i18n/en.json"},
+		}, {
+			excludes: []string{"node_modules", filepath.Join(base, "src/fixtures/**")},
+			expected: []string{"src/i18n/en.json"},
+		}, {
+			excludes: []string{filepath.Join(base, "**/i18n/en.json")},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		matches, err := Glob(filepath.Join(base, "**/*.json"), test.excludes...)
+		if err != nil {
+			t.Error(err)
+		}
+
+		for idx, match := range matches {
+			matches[idx], _ = filepath.Rel(base, match)
+		}
+
+		if !areEqual(matches, test.expected) {
+			t.Errorf("expected %v, got %v", test.expected, matches)
+		}
+	}
+}
+
+func TestGlob_invalidPattern(t *testing.T) {
+	for _, pattern := range []string{"foo/**.json", "foo**/*.json", "a/**/b/x**"} {
+		if _, err := Glob(pattern); err == nil {
+			t.Errorf("expected an error for pattern %q", pattern)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"**/en.json", "en.json", true},
+		{"**/en.json", "a/b/en.json", true},
+		{"a/**/en.json", "a/en.json", true},
+		{"a/**", "a", true},
+		{"a/**", "a/b/c", true},
+		{"a/*/en.json", "a/b/c/en.json", false},
+		{"{a,b/c}/*.json", "b/c/en.json", true},
+		{"{a,b}{,.min}.js", "a.min.js", true},
+		{"x{a,b", "x{a,b", true},
+		{"[ab]x", "bx", true},
+		{"[!ab]x", "bx", false},
+		{"\\*x", "*x", true},
+		{"\\*x", "ax", false},
+		{"?x", "ax", false},
+	}
+
+	for _, test := range tests {
+		matched, err := Match(test.pattern, test.path)
+		if err != nil {
+			t.Error(err)
+		}
+		if matched != test.expected {
+			t.Errorf("expected %q matching %q to be %v", test.path, test.pattern, test.expected)
+		}
+	}
+}
+
+func TestWalkRoot(t *testing.T) {
+	tests := []struct {
+		pattern string
+		root    string
+		depth   int
+	}{
+		{"locales/*.json", "locales", 1},
+		{"./locales/*/*.json", "locales", 2},
+		{"*.json", ".", 1},
+		{"locales/**/*.json", "locales", -1},
+		{"src/{a,b}/en.json", "src", -1},
+		{"/app/locales/[a-z]*.json", "/app/locales", 1},
+		{"locales/en.json", "locales/en.json", 0},
+	}
+
+	for _, test := range tests {
+		root, depth := WalkRoot(test.pattern)
+		if root != filepath.FromSlash(test.root) || depth != test.depth {
+			t.Errorf("%s: expected %s with depth %d, got %s with depth %d", test.pattern, test.root, test.depth, root, depth)
+		}
+	}
+}
+
 func testGlob(directories, files []string, tests map[string][]string, t *testing.T) {
 	base, err := ioutil.TempDir("", "test-glob_")
 	defer os.RemoveAll(base)
@@ -147,53 +308,3 @@ func areEqual(s, t []string) bool {
 
 	return true
 }
-
-func TestSplitAtDirGlobOperator(t *testing.T) {
-	path := "/foo/bla/bar/baz/asd/a/b/c"
-
-	tests := map[string]struct {
-		pathStart, patternStart, pathEnd, patternEnd string
-	}{
-		"/foo/**/asd/a/b/c": {
-			"/foo",
-			"/foo",
-			"asd/a/b/c",
-			"asd/a/b/c",
-		},
-		"/foo/*/bar/**/a/*/c": {
-			"/foo/bla/bar",
-			"/foo/*/bar",
-			"a/b/c",
-			"a/*/c",
-		},
-		"/**/bar/baz/*/a/*/c": {
-			"",
-			"",
-			"bar/baz/asd/a/b/c",
-			"bar/baz/*/a/*/c",
-		},
-	}
-
-	for pattern, expected := range tests {
-		pathStart, patternStart, pathEnd, patternEnd, err := SplitAtDirGlobOperator(path, pattern)
-		if err != nil {
-			t.Error(err)
-		}
-
-		if pathStart != expected.pathStart {
-			t.Errorf("expected path start to be %v, got %v", expected.pathStart, pathStart)
-		}
-
-		if patternStart != expected.patternStart {
-			t.Errorf("expected pattern start to be %v, got %v", expected.patternStart, patternStart)
-		}
-
-		if pathEnd != expected.pathEnd {
-			t.Errorf("expected path end to be %v, got %v", expected.pathEnd, pathEnd)
-		}
-
-		if patternEnd != expected.patternEnd {
-			t.Errorf("expected pattern end to be %v, got %v", expected.patternEnd, patternEnd)
-		}
-	}
-}
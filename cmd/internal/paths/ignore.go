@@ -0,0 +1,165 @@
+package paths
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the name of the file listing files which are never
+// pushed, in gitignore syntax.
+var IgnoreFileName = ".phraseignore"
+
+// Ignore holds the rules of an ignore file. A nil Ignore ignores nothing.
+type Ignore struct {
+	// dir is the absolute directory the rules are relative to.
+	dir   string
+	wd    string
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ReadIgnore reads the ignore file at path, whose rules are relative to the
+// directory of the file. It returns nil if the file does not exist.
+func ReadIgnore(path string) (*Ignore, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read ignore file: %s", err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	ignore, err := ParseIgnore(dir, string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return ignore, nil
+}
+
+// ParseIgnore parses the rules of an ignore file in dir. Like in gitignore
+// files, rules without a slash match at any depth, rules with a slash are
+// relative to dir, a trailing slash only matches directories, and rules
+// starting with ! include files again that earlier rules ignored.
+func ParseIgnore(dir, content string) (*Ignore, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	ignore := &Ignore{dir: filepath.ToSlash(dir), wd: wd}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if trimmed := strings.TrimRight(line, " "); strings.HasSuffix(trimmed, "\\") && trimmed != line {
+			// an escaped trailing space is kept
+			line = trimmed + " "
+		} else {
+			line = trimmed
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = dirGlobOperator + "/" + line
+		}
+
+		if !dirGlobOperatorUseValid(line) {
+			return nil, fmt.Errorf("line %d: the ** globbing operator may only be used as path segment on its own", i+1)
+		}
+		rule.re, err = regexp.Compile("^(?:" + translate(line, true) + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+
+		ignore.rules = append(ignore.rules, rule)
+	}
+
+	return ignore, nil
+}
+
+// Ignored returns true if the last rule matching path ignores it. Paths
+// outside of the directory of the ignore file are never ignored.
+func (ignore *Ignore) Ignored(path string, isDir bool) bool {
+	rel, ok := ignore.rel(path)
+	if !ok {
+		return false
+	}
+	return ignore.ignored(rel, isDir)
+}
+
+// IgnoredWithParents returns true if path or one of its parent directories is
+// ignored, as files in ignored directories can't be included again.
+func (ignore *Ignore) IgnoredWithParents(path string, isDir bool) bool {
+	rel, ok := ignore.rel(path)
+	if !ok {
+		return false
+	}
+
+	segments := strings.Split(rel, "/")
+	for i := 1; i < len(segments); i++ {
+		if ignore.ignored(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+	return ignore.ignored(rel, isDir)
+}
+
+// rel returns path relative to the directory of the ignore file.
+func (ignore *Ignore) rel(path string) (string, bool) {
+	if ignore == nil {
+		return "", false
+	}
+
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ignore.wd, path)
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(ignore.dir), path)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+func (ignore *Ignore) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ignore.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,121 @@
+package paths
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnore_Ignored(t *testing.T) {
+	ignore, err := ParseIgnore("/project", `
+# build output
+dist/
+/fixtures
+*.bak
+!keep.bak
+locales/**/tmp-?.json
+\#hash.json
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"/project/dist", true, true},
+		{"/project/packages/app/dist", true, true},
+		{"/project/dist", false, false},
+		{"/project/fixtures", true, true},
+		{"/project/packages/fixtures", true, false},
+		{"/project/locales/en.json.bak", false, true},
+		{"/project/locales/keep.bak", false, false},
+		{"/project/locales/a/b/tmp-1.json", false, true},
+		{"/project/locales/tmp-12.json", false, false},
+		{"/project/#hash.json", false, true},
+		{"/other/dist", true, false},
+		{"/project", true, false},
+	}
+
+	for _, test := range tests {
+		if ignored := ignore.Ignored(test.path, test.isDir); ignored != test.expected {
+			t.Errorf("expected %q to be ignored: %v, got %v", test.path, test.expected, ignored)
+		}
+	}
+}
+
+func TestIgnore_IgnoredWithParents(t *testing.T) {
+	ignore, err := ParseIgnore("/project", "build/\n!build/en.json\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ignore.IgnoredWithParents("/project/build/en.json", false) {
+		t.Errorf("expected files in an ignored directory to be ignored")
+	}
+	if ignore.IgnoredWithParents("/project/src/en.json", false) {
+		t.Errorf("expected files outside of ignored directories not to be ignored")
+	}
+
+	var none *Ignore
+	if none.IgnoredWithParents("/project/build/en.json", false) {
+		t.Errorf("expected nil to ignore nothing")
+	}
+}
+
+func TestGlobIgnoring(t *testing.T) {
+	base, err := ioutil.TempDir("", "test-glob_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	for _, dir := range []string{"src/i18n", "dist/i18n", "test/fixtures/i18n"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(base, dir, "en.json"), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignoreFile := filepath.Join(base, IgnoreFileName)
+	if err := ioutil.WriteFile(ignoreFile, []byte("dist/\nfixtures\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ignore, err := ReadIgnore(ignoreFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"**/en.json":          {"src/i18n/en.json"},
+		"dist/i18n/*.json":    {},
+		"dist/i18n/en.json":   {},
+		"*/i18n/en.json":      {"src/i18n/en.json"},
+		"src/{i18n,x}/*.json": {"src/i18n/en.json"},
+	}
+
+	for pattern, expected := range tests {
+		matches, err := GlobIgnoring(filepath.Join(base, pattern), ignore)
+		if err != nil {
+			t.Error(err)
+		}
+
+		for idx, match := range matches {
+			matches[idx], _ = filepath.Rel(base, match)
+		}
+
+		if len(matches) != len(expected) || !areEqual(matches, expected) {
+			t.Errorf("%s: expected %v, got %v", pattern, expected, matches)
+		}
+	}
+
+	if ignore, err := ReadIgnore(filepath.Join(base, "missing")); ignore != nil || err != nil {
+		t.Errorf("expected a missing ignore file to ignore nothing, got %v, %v", ignore, err)
+	}
+}
@@ -6,15 +6,24 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/phrase/phrase-cli/cmd/internal/paths"
 	"github.com/phrase/phrase-cli/cmd/internal/stringz"
 )
 
 var (
-	anyPlaceholderRegexp = regexp.MustCompile("<(locale_name|tag|locale_code)>")
-	localePlaceholder    = regexp.MustCompile("<(locale_name|locale_code)>")
+	anyPlaceholderRegexp = regexp.MustCompile("<(locale_name|tag|locale_code|locale_code_underscore|locale_code_hyphen|language|region|project_id|project_name|branch)>")
+	localePlaceholder    = regexp.MustCompile("<(locale_name|locale_code|locale_code_underscore|locale_code_hyphen|language|region)>")
 	tagPlaceholder       = regexp.MustCompile("<(tag)>")
+	contextPlaceholder   = regexp.MustCompile("<(project_id|project_name|branch)>")
 )
 
+// valuePatterns are the patterns of placeholder values in Resolve which are
+// more specific than "any path segment".
+var valuePatterns = map[string]string{
+	// the language must not swallow the separator to the region
+	"<language>": "[^/_-]+",
+}
+
 func ContainsAnyPlaceholders(s string) bool {
 	return anyPlaceholderRegexp.MatchString(s)
 }
@@ -27,6 +36,65 @@ func ContainsTagPlaceholder(s string) bool {
 	return tagPlaceholder.MatchString(s)
 }
 
+// ContainsContextPlaceholder returns true if s contains a placeholder for
+// the project or branch.
+func ContainsContextPlaceholder(s string) bool {
+	return contextPlaceholder.MatchString(s)
+}
+
+// Replace replaces the placeholders in s by values, which maps placeholder
+// names without angle brackets to their values. Other placeholders are kept.
+func Replace(s string, values map[string]string) string {
+	return anyPlaceholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+		if value, ok := values[strings.Trim(placeholder, "<>")]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// LocaleValues returns the values of the locale placeholders for the locale
+// with code and name.
+func LocaleValues(code, name string) map[string]string {
+	language, region := SplitLocaleCode(code)
+	return map[string]string{
+		"locale_code":            code,
+		"locale_name":            name,
+		"locale_code_underscore": strings.Replace(code, "-", "_", -1),
+		"locale_code_hyphen":     strings.Replace(code, "_", "-", -1),
+		"language":               language,
+		"region":                 region,
+	}
+}
+
+// SplitLocaleCode splits a locale code like "pt-BR" or "zh_Hans" into its
+// language and region at the first hyphen or underscore. The region is
+// empty if the code has none.
+func SplitLocaleCode(code string) (language, region string) {
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		return code[:i], code[i+1:]
+	}
+	return code, ""
+}
+
+// LocaleCode returns the locale code described by the values of the locale
+// placeholders of a path, as resolved by Resolve. Underscores are replaced
+// by hyphens, and the language and region are joined by a hyphen.
+func LocaleCode(values map[string]string) string {
+	switch {
+	case values["locale_code"] != "":
+		return values["locale_code"]
+	case values["locale_code_hyphen"] != "":
+		return values["locale_code_hyphen"]
+	case values["locale_code_underscore"] != "":
+		return strings.Replace(values["locale_code_underscore"], "_", "-", -1)
+	case values["language"] != "" && values["region"] != "":
+		return values["language"] + "-" + values["region"]
+	default:
+		return values["language"]
+	}
+}
+
 func ToGlobbingPattern(s string) string {
 	path := anyPlaceholderRegexp.ReplaceAllString(s, "*")
 	baseName := filepath.Base(s)
@@ -39,48 +107,56 @@ func ToGlobbingPattern(s string) string {
 
 // Resolve matches s against pattern and maps placeholders in pattern to
 // substrings of s.
-// Resolve handles '*' wildcards in the pattern, but will return an error
-// if the pattern contains '**'.
+// Resolve handles the wildcards, alternatives and character classes
+// supported by paths.Glob.
 func Resolve(s, pattern string) (map[string]string, error) {
-	s = filepath.Clean(s)
+	s = filepath.ToSlash(filepath.Clean(s))
 	pattern = filepath.Clean(pattern)
 
-	if strings.Contains(pattern, "**") {
-		return map[string]string{}, fmt.Errorf("'**' wildcard not allowed in pattern")
-	}
-
 	placeholders := anyPlaceholderRegexp.FindAllString(pattern, -1)
 	if len(placeholders) <= 0 {
 		return map[string]string{}, nil
 	}
 
-	patternRE := regexp.QuoteMeta(pattern)
-	patternRE = strings.Replace(patternRE, "\\*", ".*", -1)
+	// placeholders contain no special characters, so they are kept as they
+	// are by the translation
+	patternRE, err := paths.PatternToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, placeholder := range stringz.RemoveDuplicates(placeholders) {
+		valuePattern, ok := valuePatterns[placeholder]
+		if !ok {
+			valuePattern = "[^/]+"
+		}
 		placeholder = regexp.QuoteMeta(placeholder)
-		placeholderRE := fmt.Sprintf("(?P%s[^/]+)", placeholder) // build named subexpression (capturing group) from placeholder
+		placeholderRE := fmt.Sprintf("(?P%s%s)", placeholder, valuePattern) // build named subexpression (capturing group) from placeholder
 		patternRE = strings.Replace(patternRE, placeholder, placeholderRE, -1)
 	}
 
-	patternRegex, err := regexp.Compile(patternRE)
+	patternRegex, err := regexp.Compile("^(?:" + patternRE + ")$")
 	if err != nil {
 		return nil, err
 	}
 
 	matchNames := patternRegex.SubexpNames()
-	matches := patternRegex.FindStringSubmatch(s)
-
-	if len(matches) < len(placeholders)+1 || matches[0] != s {
+	indexes := patternRegex.FindStringSubmatchIndex(s)
+	if indexes == nil {
 		return nil, fmt.Errorf("string %q does not match pattern %q", s, patternRE)
 	}
 
-	// drop first element, which is the entire string s wich match name ""
-	matches, matchNames = matches[1:], matchNames[1:]
-
 	values := map[string]string{}
-	for i, match := range matches {
+	// skip the first pair of indexes, which is the entire string s
+	for i := 1; i < len(matchNames); i++ {
 		placeholder := matchNames[i]
+		if placeholder == "" || indexes[2*i] < 0 {
+			// a group of the translated pattern or a placeholder in an
+			// alternative that did not match
+			continue
+		}
+
+		match := s[indexes[2*i]:indexes[2*i+1]]
 		if value, ok := values[placeholder]; ok {
 			if match != value {
 				return nil, fmt.Errorf("string %q does not match pattern %q: placeholder %q is used twice with different values", s, patternRE, placeholder)
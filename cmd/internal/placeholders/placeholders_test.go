@@ -27,6 +27,29 @@ func TestResolve(t *testing.T) {
 		}: {
 			"locale_code": "en",
 		},
+		{
+			"packages/app/src/components/i18n/en.json",
+			"packages/*/src/**/i18n/<locale_code>.json",
+		}: {
+			"locale_code": "en",
+		},
+		{
+			"packages/app/src/de/i18n/components/messages.json",
+			"packages/*/**/<locale_code>/i18n/**/<tag>.json",
+		}: {
+			"locale_code": "de",
+			"tag":         "messages",
+		},
+		{
+			"locales/en.yaml",
+			"{config/,}locales/<locale_code>.{yml,yaml}",
+		}: {
+			"locale_code": "en",
+		},
+		{
+			"locales/fr/default.json",
+			"locales/[a-z][a-z]/{<tag>,default}.json",
+		}: {},
 		{
 			"abc/defg/en.lproj/Localizable.strings",
 			"./abc/defg/<locale_code>.lproj/Localizable.strings",
@@ -62,6 +85,19 @@ func TestResolve(t *testing.T) {
 			"locale_code": "en",
 			"tag":         "abc",
 		},
+		{
+			"res/values-pt-rBR/strings.xml",
+			"res/values-<language>-r<region>/strings.xml",
+		}: {
+			"language": "pt",
+			"region":   "BR",
+		},
+		{
+			"locales/zh_Hans.json",
+			"locales/<locale_code_underscore>.json",
+		}: {
+			"locale_code_underscore": "zh_Hans",
+		},
 	}
 
 	for input, expected := range tests {
@@ -127,6 +163,65 @@ func TestResolve_errorPlaceholderReuse(t *testing.T) {
 	}
 }
 
+func TestLocaleValues(t *testing.T) {
+	tests := map[string]map[string]string{
+		"pt-BR": {
+			"locale_code":            "pt-BR",
+			"locale_code_underscore": "pt_BR",
+			"locale_code_hyphen":     "pt-BR",
+			"language":               "pt",
+			"region":                 "BR",
+		},
+		"zh_Hans": {
+			"locale_code_underscore": "zh_Hans",
+			"locale_code_hyphen":     "zh-Hans",
+			"language":               "zh",
+			"region":                 "Hans",
+		},
+		"de": {
+			"locale_code_underscore": "de",
+			"language":               "de",
+			"region":                 "",
+		},
+	}
+
+	for code, expected := range tests {
+		result := LocaleValues(code, "")
+		if !areEqual(result, expected) {
+			t.Errorf("%s: got %v, but want %v", code, result, expected)
+		}
+	}
+}
+
+func TestLocaleCode(t *testing.T) {
+	tests := []struct {
+		values map[string]string
+		code   string
+	}{
+		{map[string]string{"locale_code": "en_GB", "language": "en"}, "en_GB"},
+		{map[string]string{"locale_code_underscore": "pt_BR"}, "pt-BR"},
+		{map[string]string{"language": "pt", "region": "BR"}, "pt-BR"},
+		{map[string]string{"language": "de"}, "de"},
+		{map[string]string{"tag": "web"}, ""},
+	}
+
+	for _, test := range tests {
+		if result := LocaleCode(test.values); result != test.code {
+			t.Errorf("%v: expected %q, but got %q", test.values, test.code, result)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	result := Replace("<project_id>/<branch>/<locale_code>.json", map[string]string{
+		"project_id": "abc",
+		"branch":     "feature",
+	})
+	if expected := "abc/feature/<locale_code>.json"; result != expected {
+		t.Errorf("expected %q, but got %q", expected, result)
+	}
+}
+
 func areEqual(got, want map[string]string) bool {
 	for kw, vw := range want {
 		vg, ok := got[kw]
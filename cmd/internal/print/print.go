@@ -1,9 +1,11 @@
 package print
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	ct "github.com/daviddengcn/go-colortext"
 )
@@ -48,6 +50,44 @@ const parrot = `
 
 `
 
+var (
+	jsonOutput bool
+	eventMu    sync.Mutex
+)
+
+// EnableJSON switches to machine-readable output. Event then writes JSON lines
+// to stdout, while all other messages are written to stderr without colors.
+func EnableJSON() {
+	jsonOutput = true
+}
+
+// JSON returns true if machine-readable output is enabled.
+func JSON() bool {
+	return jsonOutput
+}
+
+// Out returns the writer for human-readable messages.
+func Out() io.Writer {
+	if jsonOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// Event writes event as a single line of JSON to stdout if JSON output is
+// enabled, and does nothing otherwise.
+func Event(event interface{}) {
+	if !jsonOutput {
+		return
+	}
+
+	eventMu.Lock()
+	defer eventMu.Unlock()
+	if err := json.NewEncoder(os.Stdout).Encode(event); err != nil {
+		Error(err)
+	}
+}
+
 func Parrot() {
 	WithColor(ct.Cyan, parrot)
 }
@@ -60,8 +100,13 @@ func Failure(msg string, args ...interface{}) {
 	WithColor(ct.Red, msg, args...)
 }
 
+// Warn writes a warning to stderr.
+func Warn(msg string, args ...interface{}) {
+	fprintWithColor(os.Stderr, ct.Yellow, "WARNING: "+msg, args...)
+}
+
 func WithColor(color ct.Color, msg string, args ...interface{}) {
-	fprintWithColor(os.Stdout, color, msg, args...)
+	fprintWithColor(Out(), color, msg, args...)
 }
 
 func Error(err error) {
@@ -69,6 +114,12 @@ func Error(err error) {
 }
 
 func fprintWithColor(w io.Writer, color ct.Color, msg string, args ...interface{}) {
+	if jsonOutput {
+		fmt.Fprintf(w, msg, args...)
+		fmt.Fprintln(w)
+		return
+	}
+
 	ct.Foreground(color, true)
 	fmt.Fprintf(w, msg, args...)
 	fmt.Fprintln(w)
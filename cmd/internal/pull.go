@@ -1,15 +1,12 @@
 package internal
 
 import (
-	"context"
 	"fmt"
-	"io/ioutil"
+	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/phrase/phrase-cli/cmd/internal/paths"
 	"github.com/phrase/phrase-cli/cmd/internal/placeholders"
 	"github.com/phrase/phrase-cli/cmd/internal/print"
 
@@ -27,10 +24,16 @@ type PullCommand struct {
 	phrase.Config
 	Branch             string
 	UseLocalBranchName bool
+	DryRun             bool
+	Parallel           int
+	Watch              bool
+	Interval           time.Duration
+	Backup             bool
+	Only               string
+	Locale             string
+	OnlyTag            string
 }
 
-var Auth context.Context
-
 func (cmd *PullCommand) Run(config *phrase.Config) error {
 	Config = config
 
@@ -39,25 +42,46 @@ func (cmd *PullCommand) Run(config *phrase.Config) error {
 		Config.Debug = false
 		Debug = true
 	}
-	client := newClient()
+
+	pullConfig, err := PullConfigFromConfig(*Config)
+	if err != nil {
+		return err
+	}
+
+	parallel := cmd.Parallel
+	if parallel <= 0 {
+		parallel = pullConfig.Parallel
+	}
+
+	fileMode, err := pullConfig.GetFileMode()
+	if err != nil {
+		return err
+	}
 
 	targets, err := TargetsFromConfig(*Config)
 	if err != nil {
 		return err
 	}
 
+	selection := NewSelection(cmd.Only, cmd.Locale, cmd.OnlyTag)
+	targets, err = selection.SelectTargets(targets)
+	if err != nil {
+		return err
+	}
+
 	branchName, err := usedBranchName(cmd.UseLocalBranchName, cmd.Branch)
 	if err != nil {
 		return err
 	}
 	cmd.Branch = branchName
 
-	projectIdToLocales, err := LocalesForProjects(client, targets, cmd.Branch)
+	projectIdToLocales, err := LocalesForProjects(targets, cmd.Branch)
 	if err != nil {
 		return err
 	}
 
 	for _, target := range targets {
+		target.branch = cmd.Branch
 		val, ok := projectIdToLocales[LocaleCacheKey{target.ProjectID, cmd.Branch}]
 		if !ok || len(val) == 0 {
 			if cmd.Branch != "" {
@@ -68,25 +92,58 @@ func (cmd *PullCommand) Run(config *phrase.Config) error {
 		target.RemoteLocales = val
 	}
 
+	if selection.filtersFiles() {
+		selected := 0
+		for _, target := range targets {
+			localeFiles, err := target.LocaleFiles()
+			if err != nil {
+				return err
+			}
+			selected += len(localeFiles)
+		}
+		if selected == 0 {
+			return selection.errNoSelectedFiles()
+		}
+	}
+
+	if cmd.DryRun {
+		return targets.PrintPullPlan(cmd.Branch)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		target.fileMode = fileMode
+		target.backup = cmd.Backup
+		target.lock = lock
+	}
+
 	for _, target := range targets {
-		err := target.Pull(client, cmd.Branch)
+		err := target.Pull(target.Client(), cmd.Branch, parallel)
 		if err != nil {
+			if saveErr := lock.Save(); saveErr != nil {
+				print.Error(fmt.Errorf("could not save %s: %s", LockFileName, saveErr))
+			}
 			return err
 		}
 	}
 
-	return nil
-}
+	if err := lock.Save(); err != nil {
+		return err
+	}
 
-func newClient() *phrase.APIClient {
-	Auth = context.WithValue(context.Background(), phrase.ContextAPIKey, phrase.APIKey{
-		Key:    Config.Credentials.Token,
-		Prefix: "token",
-	})
+	if cmd.Watch {
+		interval := cmd.Interval
+		if interval <= 0 {
+			interval = DefaultWatchInterval
+		}
+		return cmd.watch(targets, interval)
+	}
 
-	cfg := phrase.NewConfiguration()
-	cfg.SetUserAgent(Config.UserAgent)
-	return phrase.NewAPIClient(cfg)
+	return nil
 }
 
 type PullParams struct {
@@ -94,7 +151,7 @@ type PullParams struct {
 	LocaleID                  string `json:"locale_id"`
 }
 
-func (target *Target) Pull(client *phrase.APIClient, branch string) error {
+func (target *Target) Pull(client *phrase.APIClient, branch string, parallel int) error {
 	if err := target.CheckPreconditions(); err != nil {
 		return err
 	}
@@ -104,22 +161,22 @@ func (target *Target) Pull(client *phrase.APIClient, branch string) error {
 		return err
 	}
 
+	if parallel > 1 && len(localeFiles) > 1 {
+		return target.pullParallel(client, localeFiles, branch, parallel)
+	}
+
 	startedAt := time.Now()
 	for _, localeFile := range localeFiles {
 		if time.Since(startedAt) >= timeoutInMinutes {
-			return fmt.Errorf("Timeout of %d minutes exceeded", timeoutInMinutes)
+			return fmt.Errorf("Timeout of %d minutes exceeded", int(timeoutInMinutes.Minutes()))
 		}
 
-		err := createFile(localeFile.Path)
-		if err != nil {
-			return err
-		}
-
-		err = target.DownloadAndWriteToFile(client, localeFile, branch)
+		written, err := target.DownloadAndWriteToFile(client, localeFile, branch)
+		print.Event(newDownloadEvent(localeFile, written, err))
 		if err != nil {
 			return fmt.Errorf("%s for %s", err, localeFile.Path)
 		} else {
-			print.Success("Downloaded %s to %s", localeFile.Message(), localeFile.RelPath())
+			localeFile.printDownloaded(written)
 		}
 		if Debug {
 			fmt.Fprintln(os.Stderr, strings.Repeat("-", 10))
@@ -129,7 +186,72 @@ func (target *Target) Pull(client *phrase.APIClient, branch string) error {
 	return nil
 }
 
-func (target *Target) DownloadAndWriteToFile(client *phrase.APIClient, localeFile *LocaleFile, branch string) error {
+// DownloadAndWriteToFile downloads localeFile and writes it to disk, unless
+// its content is the same as that of the local file. It returns false if the
+// local file was left alone.
+func (target *Target) DownloadAndWriteToFile(client *phrase.APIClient, localeFile *LocaleFile, branch string) (bool, error) {
+	localVarOptionals := target.downloadOpts(localeFile, branch)
+	state := &DownloadState{
+		LocaleID: localeFile.ID,
+		Branch:   branch,
+		Params:   sha256Hex([]byte(fmt.Sprintf("%+v", localVarOptionals))),
+	}
+	localHash := fileSHA256(localeFile.Path)
+
+	ctx := Auth
+	if target.lock != nil {
+		previous := target.lock.download(localeFile.Path)
+		// only ask for changes if the local file is what was downloaded last time
+		if previous != nil && previous.SHA256 == localHash && previous.LocaleID == state.LocaleID &&
+			previous.Branch == state.Branch && previous.Params == state.Params {
+			ctx = withIfNoneMatch(ctx, previous.ETag)
+		}
+	}
+
+	data, response, err := client.LocalesApi.LocaleDownload(ctx, target.ProjectID, localeFile.ID, &localVarOptionals)
+	if response != nil && response.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	state.SHA256 = sha256Hex(data)
+	if response != nil {
+		state.ETag = response.Header.Get("ETag")
+	}
+
+	written := false
+	if state.SHA256 != localHash {
+		if err := target.writeFile(localeFile.Path, data); err != nil {
+			return false, err
+		}
+		written = true
+	}
+
+	if target.lock != nil {
+		target.lock.setDownload(localeFile.Path, state)
+	}
+
+	return written, nil
+}
+
+func (localeFile *LocaleFile) printDownloaded(written bool) {
+	if written {
+		print.Success("Downloaded %s to %s", localeFile.Message(), localeFile.RelPath())
+	} else {
+		fmt.Fprintf(print.Out(), "%s is up to date\n", localeFile.RelPath())
+	}
+}
+
+// Download returns the content of localeFile as it is downloaded from Phrase.
+func (target *Target) Download(client *phrase.APIClient, localeFile *LocaleFile, branch string) ([]byte, error) {
+	localVarOptionals := target.downloadOpts(localeFile, branch)
+	data, _, err := client.LocalesApi.LocaleDownload(Auth, target.ProjectID, localeFile.ID, &localVarOptionals)
+	return data, err
+}
+
+func (target *Target) downloadOpts(localeFile *LocaleFile, branch string) phrase.LocaleDownloadOpts {
 	localVarOptionals := phrase.LocaleDownloadOpts{}
 
 	if target.Params != nil {
@@ -158,21 +280,7 @@ func (target *Target) DownloadAndWriteToFile(client *phrase.APIClient, localeFil
 		fmt.Fprintln(os.Stderr, "FormatOptions", localVarOptionals.FormatOptions)
 	}
 
-	data, response, err := client.LocalesApi.LocaleDownload(Auth, target.ProjectID, localeFile.ID, &localVarOptionals)
-	if err != nil {
-		if response.Rate.Remaining == 0 {
-			waitForRateLimit(response.Rate)
-			data, _, err = client.LocalesApi.LocaleDownload(Auth, target.ProjectID, localeFile.ID, &localVarOptionals)
-			if err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
-	}
-
-	err = ioutil.WriteFile(localeFile.Path, data, 0700)
-	return err
+	return localVarOptionals
 }
 
 func (target *Target) LocaleFiles() (LocaleFiles, error) {
@@ -206,7 +314,9 @@ func (target *Target) LocaleFiles() (LocaleFiles, error) {
 		return nil, fmt.Errorf("could not find any files on your system that matches the locales for project %q", target.ProjectID)
 	}
 
-	return files, nil
+	return target.selection.filter(files, func(localeFile *LocaleFile) []string {
+		return []string{localeFile.Tag}
+	}), nil
 }
 
 func (target *Target) createLocaleFiles(remoteLocale *phrase.Locale) (LocaleFiles, error) {
@@ -232,13 +342,6 @@ func (target *Target) createLocaleFiles(remoteLocale *phrase.Locale) (LocaleFile
 	return files, nil
 }
 
-func waitForRateLimit(response phrase.Rate) {
-	var reset time.Time
-	resetTime := reset.Add(time.Second * 5).Sub(time.Now())
-	fmt.Printf("Rate limit exceeded. Download will resume in %d seconds\n", int64(resetTime.Seconds()))
-	time.Sleep(resetTime)
-}
-
 func createLocaleFile(target *Target, remoteLocale *phrase.Locale, tag string) (*LocaleFile, error) {
 	localeFile := &LocaleFile{
 		Name:       remoteLocale.Name,
@@ -257,22 +360,3 @@ func createLocaleFile(target *Target, remoteLocale *phrase.Locale, tag string) (
 	localeFile.Path = absPath
 	return localeFile, nil
 }
-
-func createFile(path string) error {
-	err := paths.Exists(path)
-	if err != nil {
-		absDir := filepath.Dir(path)
-		err := paths.Exists(absDir)
-		if err != nil {
-			_ = os.MkdirAll(absDir, 0700)
-		}
-
-		f, err := os.Create(path)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-	}
-
-	return nil
-}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/phrase/phrase-cli/cmd/internal/paths"
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+)
+
+// PrintPullPlan prints every file a pull would create or overwrite without
+// downloading anything from Phrase.
+func (targets Targets) PrintPullPlan(branch string) error {
+	w := tabwriter.NewWriter(print.Out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tACTION\tLOCALE ID\tLOCALE NAME\tTAG\tBRANCH\tFORMAT")
+
+	for _, target := range targets {
+		if err := target.CheckPreconditions(); err != nil {
+			return err
+		}
+
+		if len(target.RemoteLocales) == 0 {
+			// the branch does not exist in this target's project
+			continue
+		}
+
+		localeFiles, err := target.LocaleFiles()
+		if err != nil {
+			return err
+		}
+
+		for _, localeFile := range localeFiles {
+			action := "overwrite"
+			if paths.Exists(localeFile.Path) != nil {
+				action = "create"
+			}
+
+			event := plannedDownloadEvent{
+				Event:      "planned_download",
+				File:       localeFile.RelPath(),
+				Action:     action,
+				LocaleID:   localeFile.ID,
+				LocaleName: localeFile.Name,
+				Tag:        localeFile.Tag,
+				Branch:     branch,
+				Format:     localeFile.FileFormat,
+			}
+			print.Event(event)
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				event.File,
+				event.Action,
+				event.LocaleID,
+				orDash(event.LocaleName),
+				orDash(event.Tag),
+				orDash(event.Branch),
+				orDash(event.Format),
+			)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(print.Out())
+	fmt.Fprintln(print.Out(), "Dry run: no files were written.")
+	return nil
+}
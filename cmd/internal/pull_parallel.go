@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-cli/cmd/internal/spinner"
+	"github.com/phrase/phrase-go"
+)
+
+// pullResult is the outcome of downloading a single locale file.
+type pullResult struct {
+	localeFile *LocaleFile
+	written    bool
+	err        error
+}
+
+// pullParallel downloads localeFiles using up to parallel concurrent workers,
+// which share a single rate limit budget. Results are printed in the order of
+// localeFiles once all downloads are done.
+func (target *Target) pullParallel(client *phrase.APIClient, localeFiles LocaleFiles, branch string, parallel int) error {
+	results := make([]*pullResult, len(localeFiles))
+	jobs := make(chan int)
+	startedAt := time.Now()
+
+	fmt.Fprintf(print.Out(), "Downloading %d files using %d workers... ", len(localeFiles), parallel)
+	spinner.While(func() {
+		var wg sync.WaitGroup
+		for w := 0; w < parallel; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					result := &pullResult{localeFile: localeFiles[i]}
+					if time.Since(startedAt) >= timeoutInMinutes {
+						result.err = fmt.Errorf("Timeout of %d minutes exceeded", int(timeoutInMinutes.Minutes()))
+					} else {
+						result.written, result.err = target.DownloadAndWriteToFile(client, localeFiles[i], branch)
+					}
+					results[i] = result
+				}
+			}()
+		}
+
+		for i := range localeFiles {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	})
+	fmt.Fprintln(print.Out())
+
+	var firstErr error
+	for _, result := range results {
+		print.Event(newDownloadEvent(result.localeFile, result.written, result.err))
+		if result.err != nil {
+			print.Failure("Failed to download %s: %s", result.localeFile.RelPath(), result.err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s for %s", result.err, result.localeFile.Path)
+			}
+			continue
+		}
+		result.localeFile.printDownloaded(result.written)
+	}
+
+	return firstErr
+}
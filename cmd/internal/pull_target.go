@@ -3,9 +3,12 @@ package internal
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/phrase/phrase-cli/cmd/internal/interpolate"
 	"github.com/phrase/phrase-cli/cmd/internal/paths"
 	"github.com/phrase/phrase-cli/cmd/internal/placeholders"
 	"github.com/phrase/phrase-cli/cmd/internal/shared"
@@ -23,13 +26,43 @@ func (targets Targets) ProjectIds() []string {
 	return projectIds
 }
 
+func (targets Targets) ClientForProject(projectId string) *phrase.APIClient {
+	for _, target := range targets {
+		if target.ProjectID == projectId {
+			return target.Client()
+		}
+	}
+	return newClient()
+}
+
 type Target struct {
+	Name          string      `json:"name"`
 	File          string      `json:"file"`
 	ProjectID     string      `json:"project_id"`
+	Host          string      `json:"host"`
 	AccessToken   string      `json:"access_token"`
 	FileFormat    string      `json:"file_format"`
 	Params        *PullParams `json:"params" mapstructure:"omittable-nested,omitempty"`
+	// LocaleMapping maps the locale codes or names in file paths to the
+	// codes, names or ids of remote locales.
+	LocaleMapping LocaleMapping `json:"locale_mapping"`
 	RemoteLocales []*phrase.Locale
+
+	// fileMode is the mode of newly created files.
+	fileMode os.FileMode
+	// backup keeps the previous version of every overwritten file.
+	backup bool
+	// lock records the state of every downloaded file if it is set.
+	lock *LockFile
+	// selection narrows the locale files of the target if it is set.
+	selection *Selection
+	// branch is the branch RemoteLocales belong to.
+	branch string
+}
+
+// Client returns the API client for the host and access token of target.
+func (target *Target) Client() *phrase.APIClient {
+	return clientFor(target.Host, target.AccessToken)
 }
 
 func (target *Target) CheckPreconditions() error {
@@ -99,7 +132,7 @@ func containsInvalidTagInformation(target *Target) error {
 //
 func (target *Target) localeForRemote() (*phrase.Locale, error) {
 	for _, locale := range target.RemoteLocales {
-		if locale.Id == target.GetLocaleID() || locale.Name == target.GetLocaleID() {
+		if locale.Id == target.GetLocaleID() || locale.Name == target.GetLocaleID() || locale.Code == target.GetLocaleID() {
 			return locale, nil
 		}
 	}
@@ -112,11 +145,34 @@ func (target *Target) ReplacePlaceholders(localeFile *LocaleFile) (string, error
 		return "", err
 	}
 
-	path := strings.Replace(absPath, "<locale_name>", localeFile.Name, -1)
-	path = strings.Replace(path, "<locale_code>", localeFile.Code, -1)
-	path = strings.Replace(path, "<tag>", localeFile.Tag, -1)
+	name, code := localeFile.Name, localeFile.Code
+	if local, field, ok := target.LocaleMapping.toLocal(localeFile); ok {
+		switch field {
+		case "code":
+			code = local
+		case "name":
+			name = local
+		default:
+			// the id has no placeholder, so the local value may stand for
+			// either of them
+			name, code = local, local
+		}
+	}
+
+	values := placeholders.LocaleValues(code, name)
+	values["tag"] = localeFile.Tag
+	if placeholders.ContainsContextPlaceholder(absPath) {
+		context, err := contextValues(absPath, target.Client(), target.ProjectID, target.branch)
+		if err != nil {
+			return "", err
+		}
+		for placeholder, value := range context {
+			values[placeholder] = value
+		}
+	}
 
-	return path, nil
+	// an empty branch must not leave an empty path segment
+	return filepath.Clean(placeholders.Replace(absPath, values)), nil
 }
 
 func (t *Target) GetFormat() string {
@@ -153,31 +209,74 @@ func (t *Target) GetTags() []string {
 	return tagList
 }
 
-func TargetsFromConfig(config phrase.Config) (Targets, error) {
+// PullConfig contains the settings of the pull section of a config file.
+type PullConfig struct {
+	Targets       Targets       `json:"targets"`
+	Parallel      int           `json:"parallel"`
+	FileMode      string        `json:"file_mode"`
+	LocaleMapping LocaleMapping `json:"locale_mapping"`
+}
+
+// GetFileMode returns the mode of newly created files, which is given in
+// octal notation, e.g. "0644".
+func (pullConfig *PullConfig) GetFileMode() (os.FileMode, error) {
+	if pullConfig.FileMode == "" {
+		return DefaultFileMode, nil
+	}
+
+	mode, err := strconv.ParseUint(pullConfig.FileMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file_mode %q, expected permissions in octal notation like \"0644\"", pullConfig.FileMode)
+	}
+
+	return os.FileMode(mode), nil
+}
+
+func PullConfigFromConfig(config phrase.Config) (*PullConfig, error) {
 	if config.Targets == nil || len(config.Targets) == 0 {
 		return nil, fmt.Errorf("no targets for download specified")
 	}
 
-	tmp := struct {
-		Targets Targets
-	}{}
+	pullConfig := new(PullConfig)
+
+	raw, err := expandSection("pull", config.Targets)
+	if err != nil {
+		return nil, err
+	}
 
 	targets := viper.New()
 	targets.SetConfigType("yaml")
-	err := targets.ReadConfig(bytes.NewReader(config.Targets))
+	err = targets.ReadConfig(bytes.NewReader(raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = targets.UnmarshalExact(pullConfig, ViperStructTag())
+	if err != nil {
+		return nil, err
+	}
 
+	pullConfig.LocaleMapping, err = sectionLocaleMapping(raw)
 	if err != nil {
 		return nil, err
 	}
 
-	err = targets.UnmarshalExact(&tmp, ViperStructTag())
+	return pullConfig, nil
+}
+
+func TargetsFromConfig(config phrase.Config) (Targets, error) {
+	pullConfig, err := PullConfigFromConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	tgts := tmp.Targets
+	tgts := pullConfig.Targets
 
-	projectId := config.DefaultProjectID
+	projectId, err := interpolate.Env(config.DefaultProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("project_id: %s", err)
+	}
 	fileFormat := config.DefaultFileFormat
 
 	validTargets := []*Target{}
@@ -191,6 +290,7 @@ func TargetsFromConfig(config phrase.Config) (Targets, error) {
 		if target.FileFormat == "" {
 			target.FileFormat = fileFormat
 		}
+		target.LocaleMapping = pullConfig.LocaleMapping.merge(target.LocaleMapping)
 		validTargets = append(validTargets, target)
 	}
 
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+)
+
+// DefaultWatchInterval is how often pull --watch checks for changed locales by
+// default.
+const DefaultWatchInterval = 30 * time.Second
+
+// localeVersions maps the id of every remote locale to its last update.
+type localeVersions map[string]time.Time
+
+func newLocaleVersions(locales []*phrase.Locale) localeVersions {
+	versions := localeVersions{}
+	for _, locale := range locales {
+		versions[locale.Id] = locale.UpdatedAt
+	}
+	return versions
+}
+
+// changed returns the ids of all locales which were added or updated since
+// versions was taken.
+func (versions localeVersions) changed(locales []*phrase.Locale) map[string]bool {
+	changed := map[string]bool{}
+	for _, locale := range locales {
+		if updatedAt, ok := versions[locale.Id]; !ok || !updatedAt.Equal(locale.UpdatedAt) {
+			changed[locale.Id] = true
+		}
+	}
+	return changed
+}
+
+// watch polls the remote locales of targets every interval and downloads the
+// files of every locale which was updated since, until the process is
+// interrupted.
+func (cmd *PullCommand) watch(targets Targets, interval time.Duration) error {
+	versions := map[LocaleCacheKey]localeVersions{}
+	for _, target := range targets {
+		key := LocaleCacheKey{target.ProjectID, cmd.Branch}
+		versions[key] = newLocaleVersions(target.RemoteLocales)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	fmt.Fprintf(print.Out(), "\nChecking for changed locales every %s. Press Ctrl+C to stop.\n", interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			projectIdToLocales, err := LocalesForProjects(targets, cmd.Branch)
+			if err != nil {
+				print.Error(err)
+				continue
+			}
+
+			changed := map[LocaleCacheKey]map[string]bool{}
+			for key, locales := range projectIdToLocales {
+				changed[key] = versions[key].changed(locales)
+				versions[key] = newLocaleVersions(locales)
+			}
+
+			for _, target := range targets {
+				key := LocaleCacheKey{target.ProjectID, cmd.Branch}
+				if len(changed[key]) == 0 {
+					continue
+				}
+
+				target.RemoteLocales = projectIdToLocales[key]
+				target.pullChanged(target.Client(), changed[key], cmd.Branch)
+				if target.lock != nil {
+					if err := target.lock.Save(); err != nil {
+						print.Error(err)
+					}
+				}
+			}
+		case <-interrupt:
+			return nil
+		}
+	}
+}
+
+// pullChanged downloads the locale files of target that belong to one of the
+// changed locales.
+func (target *Target) pullChanged(client *phrase.APIClient, changed map[string]bool, branch string) {
+	localeFiles, err := target.LocaleFiles()
+	if err != nil {
+		print.Error(err)
+		return
+	}
+
+	for _, localeFile := range localeFiles {
+		if !changed[localeFile.ID] {
+			continue
+		}
+
+		written, err := target.DownloadAndWriteToFile(client, localeFile, branch)
+		print.Event(newDownloadEvent(localeFile, written, err))
+		if err != nil {
+			print.Failure("Failed to download %s: %s", localeFile.RelPath(), err)
+			continue
+		}
+		localeFile.printDownloaded(written)
+	}
+}
@@ -23,7 +23,18 @@ type PushCommand struct {
 	Wait               bool
 	Branch             string
 	UseLocalBranchName bool
-    Tag                string
+	Tag                string
+	DryRun             bool
+	Parallel           int
+	FailFast           bool
+	Watch              bool
+	Force              bool
+	Only               string
+	Locale             string
+	OnlyTag            string
+
+	// lock records the files pushed before if it is set.
+	lock *LockFile
 }
 
 func (cmd *PushCommand) Run() error {
@@ -34,18 +45,31 @@ func (cmd *PushCommand) Run() error {
 	}
 	Config = &cmd.Config
 
-	client := newClient()
+	pushConfig, err := PushConfigFromConfig(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Parallel <= 0 {
+		cmd.Parallel = pushConfig.Parallel
+	}
 
 	sources, err := SourcesFromConfig(cmd.Config)
 	if err != nil {
 		return err
 	}
 
+	selection := NewSelection(cmd.Only, cmd.Locale, cmd.OnlyTag)
+	sources, err = selection.SelectSources(sources)
+	if err != nil {
+		return err
+	}
+
 	if err := sources.Validate(); err != nil {
 		return err
 	}
 
-	formatMap, err := formatsByApiName(client)
+	formatMap, err := formatsByApiName(sources[0].Client())
 	if err != nil {
 		return fmt.Errorf("Error retrieving format list from Phrase: %s", err)
 	}
@@ -74,8 +98,14 @@ func (cmd *PushCommand) Run() error {
 
 	if cmd.Branch != "" {
 		for projectId := range projectsAffected {
+			client := sources.ClientForProject(projectId)
 			_, _, err := client.BranchesApi.BranchShow(Auth, projectId, cmd.Branch, nil)
 			if err != nil {
+				if cmd.DryRun {
+					fmt.Fprintf(print.Out(), "Branch %s does not exist in project %s and would be created.\n", cmd.Branch, projectId)
+					continue
+				}
+
 				if useLocalBranchName(cmd.UseLocalBranchName) {
 					printCreateBranchQuestion(cmd.Branch)
 					text, _ := bufio.NewReader(os.Stdin).ReadString('\n')
@@ -91,18 +121,18 @@ func (cmd *PushCommand) Run() error {
 					return err
 				}
 
-				fmt.Println()
+				fmt.Fprintln(print.Out())
 
 				taskResult := make(chan string, 1)
 				taskErr := make(chan error, 1)
 
-				fmt.Printf("Waiting for branch %s is created!", branch.Name)
+				fmt.Fprintf(print.Out(), "Waiting for branch %s is created!", branch.Name)
 				spinner.While(func() {
 					branchCreateResult, err := getBranchCreateResult(client, projectId, &branch)
 					taskResult <- branchCreateResult
 					taskErr <- err
 				})
-				fmt.Println()
+				fmt.Fprintln(print.Out())
 
 				if err := <-taskErr; err != nil {
 					return err
@@ -118,88 +148,186 @@ func (cmd *PushCommand) Run() error {
 		}
 	}
 
-	projectIdToLocales, err := LocalesForProjects(client, sources, cmd.Branch)
+	projectIdToLocales, err := LocalesForProjects(sources, cmd.Branch)
 	if err != nil {
 		return err
 	}
 	for _, source := range sources {
+		source.branch = cmd.Branch
 		val, ok := projectIdToLocales[LocaleCacheKey{source.ProjectID, cmd.Branch}]
 		if ok {
 			source.RemoteLocales = val
 		}
 	}
 
+	// check all sources before uploading anything
+	selected := 0
+	matchErr := &LocaleMatchError{}
+	for _, source := range sources {
+		localeFiles, err := source.LocaleFiles()
+		if sourceMatchErr, ok := err.(*LocaleMatchError); ok {
+			matchErr.add(sourceMatchErr)
+			continue
+		} else if err != nil {
+			return err
+		}
+		selected += len(localeFiles)
+	}
+	if len(matchErr.problems) > 0 {
+		return matchErr
+	}
+	if selection.filtersFiles() && selected == 0 {
+		return selection.errNoSelectedFiles()
+	}
+
+	if cmd.DryRun {
+		return sources.PrintPushPlan(cmd.Branch, cmd.Tag)
+	}
+
+	cmd.lock, err = LoadLockFile()
+	if err != nil {
+		return err
+	}
+
+	summary := &pushSummary{}
 	for _, source := range sources {
-		err := source.Push(client, cmd.Wait, cmd.Branch, cmd.Tag)
+		results, err := source.Push(source.Client(), cmd)
+		summary.add(results)
+		source.recordPush(results, cmd)
 		if err != nil {
+			// the uploads so far are recorded, but the push error is the
+			// one to return
+			if saveErr := cmd.lock.Save(); saveErr != nil {
+				print.Error(fmt.Errorf("could not save %s: %s", LockFileName, saveErr))
+			}
 			return err
 		}
+
+		if cmd.FailFast && summary.Failed > 0 {
+			break
+		}
+	}
+
+	summary.print()
+
+	if err := cmd.lock.Save(); err != nil {
+		return err
+	}
+
+	if summary.Failed > 0 {
+		if !cmd.Watch || cmd.FailFast {
+			return &PushFailedError{Failed: summary.Failed}
+		}
+		print.Warn("%d file(s) could not be pushed, they are pushed again once they change", summary.Failed)
+	}
+
+	if cmd.Watch {
+		return cmd.watch(sources)
 	}
 
 	return nil
 }
 
-func (source *Source) Push(client *phrase.APIClient, waitForResults bool, branch string, tag string) error {
+// Push uploads all locale files of source and returns the outcome per file.
+// Files which cannot be uploaded or processed do not stop the push, unless
+// cmd.FailFast is set.
+func (source *Source) Push(client *phrase.APIClient, cmd *PushCommand) ([]*pushResult, error) {
 	localeFiles, err := source.LocaleFiles()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if cmd.Parallel > 1 && len(localeFiles) > 1 {
+		return source.pushParallel(client, localeFiles, cmd), nil
 	}
 
+	results := []*pushResult{}
 	for _, localeFile := range localeFiles {
-		fmt.Printf("Uploading %s... ", localeFile.RelPath())
+		result := &pushResult{localeFile: localeFile}
+		results = append(results, result)
+
+		if cmd.FailFast && anyFailed(results) {
+			result.skipped = true
+			result.event()
+			continue
+		}
 
-		if localeFile.shouldCreateLocale(source, branch) {
-			localeDetails, err := source.createLocale(client, localeFile, branch)
+		source.checkUnchanged(result, cmd)
+		if result.unchanged {
+			result.print()
+			result.event()
+			continue
+		}
+
+		fmt.Fprintf(print.Out(), "Uploading %s... ", localeFile.RelPath())
+
+		if localeFile.shouldCreateLocale(source, cmd.Branch) {
+			localeDetails, created, err := source.createLocale(client, localeFile, cmd.Branch)
 			if err == nil {
 				localeFile.ID = localeDetails.Id
 				localeFile.Code = localeDetails.Code
 				localeFile.Name = localeDetails.Name
+				result.createdLocale = created
 			} else {
-				fmt.Printf("failed to create locale: %s\n", err)
+				fmt.Fprintf(print.Out(), "failed to create locale: %s\n", err)
+				result.err = fmt.Errorf("failed to create locale: %s", err)
+				result.event()
 				continue
 			}
 		}
 
-		upload, err := source.uploadFile(client, localeFile, branch, tag)
+		upload, err := source.uploadFile(client, localeFile, cmd.Branch, cmd.Tag)
 		if err != nil {
-			return err
+			fmt.Fprintf(print.Out(), "failed: %s\n", err)
+			result.err = err
+			result.event()
+			continue
 		}
+		result.upload = upload
 
-		if waitForResults {
-			fmt.Println()
+		if cmd.Wait {
+			fmt.Fprintln(print.Out())
 
-			taskResult := make(chan string, 1)
+			taskResult := make(chan *phrase.Upload, 1)
 			taskErr := make(chan error, 1)
 
-			fmt.Printf("Upload Id: %s, filename: %s succeeded. Waiting for your file to be processed... ", upload.Id, upload.Filename)
+			fmt.Fprintf(print.Out(), "Upload Id: %s, filename: %s succeeded. Waiting for your file to be processed... ", upload.Id, upload.Filename)
 			spinner.While(func() {
-				result, err := getUploadResult(client, source.ProjectID, upload, branch)
-				taskResult <- result
+				processed, err := getUploadResult(client, source.ProjectID, upload, cmd.Branch)
+				taskResult <- processed
 				taskErr <- err
 			})
-			fmt.Println()
+			fmt.Fprintln(print.Out())
+
+			result.upload = <-taskResult
+			result.waited = true
 
 			if err := <-taskErr; err != nil {
-				return err
+				result.err = err
+				print.Failure("Could not get the processing result of %s: %s", localeFile.RelPath(), err)
+				result.event()
+				continue
 			}
 
-			switch <-taskResult {
+			switch result.upload.State {
 			case "success":
 				print.Success("Successfully uploaded and processed %s.", localeFile.RelPath())
 			case "error":
 				print.Failure("There was an error processing %s. Your changes were not saved online.", localeFile.RelPath())
 			}
 		} else {
-			fmt.Println("done!")
-			fmt.Printf("Check upload Id: %s, filename: %s for information about processing results.\n", upload.Id, upload.Filename)
+			fmt.Fprintln(print.Out(), "done!")
+			fmt.Fprintf(print.Out(), "Check upload Id: %s, filename: %s for information about processing results.\n", upload.Id, upload.Filename)
 		}
 
+		result.event()
+
 		if Debug {
 			fmt.Fprintln(os.Stderr, strings.Repeat("-", 10))
 		}
 	}
 
-	return nil
+	return results, nil
 }
 
 func formatsByApiName(client *phrase.APIClient) (map[string]*phrase.Format, error) {
@@ -208,35 +336,79 @@ func formatsByApiName(client *phrase.APIClient) (map[string]*phrase.Format, erro
 		return nil, err
 	}
 	formatMap := map[string]*phrase.Format{}
-	for _, format := range formats {
-		formatMap[format.ApiName] = &format
+	for i := range formats {
+		formatMap[formats[i].ApiName] = &formats[i]
 	}
 	return formatMap, nil
 }
 
 // Return all locale files from disk that match the source pattern.
 func (source *Source) LocaleFiles() (LocaleFiles, error) {
-	filePaths, err := paths.Glob(placeholders.ToGlobbingPattern(source.File))
+	pattern, err := source.filePattern()
+	if err != nil {
+		return nil, err
+	}
+
+	excludes := source.excludePatterns()
+	for i, exclude := range excludes {
+		if placeholders.ContainsAnyPlaceholders(exclude) {
+			excludes[i] = placeholders.ToGlobbingPattern(exclude)
+		}
+	}
+
+	ignore, err := paths.ReadIgnore(paths.IgnoreFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	filePaths, err := paths.GlobIgnoring(placeholders.ToGlobbingPattern(pattern), ignore, excludes...)
 	if err != nil {
 		return nil, err
 	}
 
 	var localeFiles LocaleFiles
+	matchErr := &LocaleMatchError{}
 	for _, path := range filePaths {
 		if paths.IsPhraseAppYmlConfig(path) {
 			continue
 		}
 
 		localeFile := new(LocaleFile)
-		localeFile.fillFromPath(path, source.File)
-
 		localeFile.Path, err = filepath.Abs(path)
 		if err != nil {
 			return nil, err
 		}
 
-		locale := source.getRemoteLocaleForLocaleFile(localeFile)
-		// TODO: sinnvoll?
+		if err := localeFile.fillFromPath(path, pattern, source.LocaleMapping); err != nil {
+			// the glob is broader than the placeholders, e.g. <language>
+			// does not match a language and region
+			if source.strictLocales {
+				matchErr.problems = append(matchErr.problems, &localeMatchProblem{
+					localeFile: localeFile,
+					message:    fmt.Sprintf("does not match the placeholders of the file pattern (%s)", err),
+				})
+				continue
+			}
+			// LocaleFiles is called more than once per push
+			if !source.warned[path] {
+				print.Warn("Skipping %s, it does not match the placeholders of the file pattern: %s", localeFile.RelPath(), err)
+				if source.warned == nil {
+					source.warned = map[string]bool{}
+				}
+				source.warned[path] = true
+			}
+			continue
+		}
+
+		locale, problem := source.getRemoteLocaleForLocaleFile(localeFile)
+		if problem != nil {
+			// files which are not selected are not uploaded, so they do not
+			// need to match
+			if len(source.selection.filter(LocaleFiles{localeFile}, source.localeFileTags)) > 0 {
+				matchErr.problems = append(matchErr.problems, problem)
+			}
+			continue
+		}
 		if locale != nil {
 			localeFile.ExistsRemote = true
 			localeFile.Code = locale.Code
@@ -245,7 +417,7 @@ func (source *Source) LocaleFiles() (LocaleFiles, error) {
 		}
 
 		if Debug {
-			fmt.Printf(
+			fmt.Fprintf(print.Out(),
 				"Code:%q, Name:%q, Id:%q, Tag:%q\n",
 				localeFile.Code, localeFile.Name, localeFile.ID, localeFile.Tag,
 			)
@@ -254,18 +426,30 @@ func (source *Source) LocaleFiles() (LocaleFiles, error) {
 		localeFiles = append(localeFiles, localeFile)
 	}
 
+	if len(matchErr.problems) > 0 {
+		return nil, matchErr
+	}
+
 	if len(localeFiles) == 0 {
-		abs, err := filepath.Abs(source.File)
+		abs, err := filepath.Abs(pattern)
 		if err != nil {
-			abs = source.File
+			abs = pattern
 		}
 		return nil, fmt.Errorf("Could not find any files on your system that matches: '%s'", abs)
 	}
 
-	return localeFiles, nil
+	return source.selection.filter(localeFiles, source.localeFileTags), nil
+}
+
+// localeFileTags returns the tags localeFile is uploaded with.
+func (source *Source) localeFileTags(localeFile *LocaleFile) []string {
+	return splitList(source.uploadTags(localeFile, ""))
 }
 
-func (source *Source) getRemoteLocaleForLocaleFile(localeFile *LocaleFile) *phrase.Locale {
+// getRemoteLocaleForLocaleFile returns the remote locale localeFile is
+// uploaded to, or nil if there is none. In strict mode a problem is returned
+// instead if the file matches several locales or only part of a locale name.
+func (source *Source) getRemoteLocaleForLocaleFile(localeFile *LocaleFile) (*phrase.Locale, *localeMatchProblem) {
 	candidates := source.RemoteLocales
 
 	filterApplied := false
@@ -284,75 +468,147 @@ func (source *Source) getRemoteLocaleForLocaleFile(localeFile *LocaleFile) *phra
 		return tmpCands
 	}
 
+	if mapped := localeFile.mappedLocale; mapped != "" {
+		candidates = filter(candidates, mapped, func(cand *phrase.Locale) bool {
+			return cand.Id == mapped || cand.Name == mapped || cand.Code == mapped
+		})
+		if len(candidates) == 0 && source.strictLocales {
+			return nil, &localeMatchProblem{
+				localeFile: localeFile,
+				message:    fmt.Sprintf("is mapped to %q by locale_mapping, which is not a locale in Phrase", mapped),
+			}
+		}
+		return source.singleRemoteLocale(localeFile, candidates)
+	}
+
+	// narrow filters candidates by the locale name and code of the file path
+	narrow := func(cands []*phrase.Locale) []*phrase.Locale {
+		cands = filter(cands, localeFile.Name, func(cand *phrase.Locale) bool {
+			return cand.Name == localeFile.Name
+		})
+		if len(localeFile.derived) == 0 {
+			return filter(cands, localeFile.Code, func(cand *phrase.Locale) bool {
+				return cand.Code == localeFile.Code
+			})
+		}
+
+		// the path only contains parts or variants of the code
+		cands = filter(cands, localeFile.Code, func(cand *phrase.Locale) bool {
+			values := placeholders.LocaleValues(cand.Code, cand.Name)
+			for placeholder, value := range localeFile.derived {
+				if values[placeholder] != value {
+					return false
+				}
+			}
+			return true
+		})
+		if len(cands) > 1 {
+			// prefer pt over pt-BR for a path with only the language pt
+			exact := filter(cands, localeFile.Code, func(cand *phrase.Locale) bool {
+				return cand.Code == localeFile.Code
+			})
+			if len(exact) == 1 {
+				return exact
+			}
+		}
+		return cands
+	}
+
 	localeName := source.replacePlaceholderInParams(localeFile)
 	if localeName != "" {
 		// This means the name can contain the value specified in LocaleId, with
 		// `<locale_code>` being substituted by the value of the currently handled
 		// localeFile (like push only locales with name `en-US`).
-		candidates = filter(candidates, localeName, func(cand *phrase.Locale) bool {
+		partial := narrow(filter(candidates, localeName, func(cand *phrase.Locale) bool {
 			return strings.Contains(cand.Name, localeName)
-		})
-	} else {
-		localeId := source.GetLocaleID()
-		candidates = filter(candidates, localeId, func(cand *phrase.Locale) bool {
-			return cand.Name == localeId || cand.Id == localeId
-		})
-	}
+		}))
+		if !source.strictLocales {
+			return source.singleRemoteLocale(localeFile, partial)
+		}
 
-	candidates = filter(candidates, localeFile.Name, func(cand *phrase.Locale) bool {
-		return cand.Name == localeFile.Name
-	})
+		exact := narrow(filter(candidates, localeName, func(cand *phrase.Locale) bool {
+			return cand.Name == localeName || cand.Code == localeName
+		}))
+		if len(exact) == 0 && len(partial) > 0 {
+			return nil, &localeMatchProblem{
+				localeFile: localeFile,
+				message:    fmt.Sprintf("only matches locales whose names contain %q", localeName),
+				candidates: partial,
+			}
+		}
+		return source.singleRemoteLocale(localeFile, exact)
+	}
 
-	candidates = filter(candidates, localeFile.Code, func(cand *phrase.Locale) bool {
-		return cand.Code == localeFile.Code
-	})
+	localeId := source.GetLocaleID()
+	candidates = narrow(filter(candidates, localeId, func(cand *phrase.Locale) bool {
+		return cand.Name == localeId || cand.Id == localeId || cand.Code == localeId
+	}))
 
 	// If no filter was applied the candidates list still contains all remote
 	// locales, while actually nothing matches.
 	if !filterApplied {
-		return nil
+		return nil, nil
 	}
 
-	switch len(candidates) {
-	case 0:
-		return nil
-	case 1:
-		return candidates[0]
+	return source.singleRemoteLocale(localeFile, candidates)
+}
+
+// singleRemoteLocale returns the only one of candidates. If there are several,
+// the first is returned, or a problem in strict mode.
+func (source *Source) singleRemoteLocale(localeFile *LocaleFile, candidates []*phrase.Locale) (*phrase.Locale, *localeMatchProblem) {
+	switch {
+	case len(candidates) == 0:
+		return nil, nil
+	case len(candidates) == 1 || !source.strictLocales:
+		return candidates[0], nil
 	default:
-		// TODO I guess this should return an error, as this is a problem.
-		return candidates[0]
+		return nil, &localeMatchProblem{
+			localeFile: localeFile,
+			message:    "matches several locales",
+			candidates: candidates,
+		}
 	}
 }
 
-func (localeFile *LocaleFile) fillFromPath(path, pattern string) {
-	path = filepath.ToSlash(path)
-	pathStart, patternStart, pathEnd, patternEnd, err := paths.SplitAtDirGlobOperator(path, pattern)
+// fillFromPath sets the locale code, name and tag of localeFile from the
+// placeholders of pattern. Locale codes and names are translated to remote
+// locales by mapping. An error is returned if path does not match pattern.
+func (localeFile *LocaleFile) fillFromPath(path, pattern string, mapping LocaleMapping) error {
+	params, err := placeholders.Resolve(path, pattern)
 	if err != nil {
-		print.Error(err)
-		return
+		return err
 	}
 
-	fillFrom := func(path, pattern string) {
-		params, err := placeholders.Resolve(path, pattern)
-		if err != nil {
-			print.Error(err)
-			return
-		}
-
-		for placeholder, value := range params {
-			switch placeholder {
-			case "locale_code":
-				localeFile.Code = value
-			case "locale_name":
-				localeFile.Name = value
-			case "tag":
-				localeFile.Tag = value
+	for placeholder, value := range params {
+		switch placeholder {
+		case "locale_code":
+			localeFile.Code = value
+		case "locale_name":
+			localeFile.Name = value
+		case "tag":
+			localeFile.Tag = value
+		case "locale_code_underscore", "locale_code_hyphen", "language", "region":
+			if localeFile.derived == nil {
+				localeFile.derived = map[string]string{}
 			}
+			localeFile.derived[placeholder] = value
 		}
 	}
 
-	fillFrom(pathStart, patternStart)
-	fillFrom(pathEnd, patternEnd)
+	if localeFile.Code == "" && len(localeFile.derived) > 0 {
+		localeFile.Code = placeholders.LocaleCode(localeFile.derived)
+	}
+
+	if remote, ok := mapping.toRemote(localeFile.Code); ok {
+		localeFile.Code = remote
+		localeFile.mappedLocale = remote
+	}
+	if remote, ok := mapping.toRemote(localeFile.Name); ok {
+		localeFile.Name = remote
+		localeFile.mappedLocale = remote
+	}
+
+	return nil
 }
 
 func (localeFile *LocaleFile) shouldCreateLocale(source *Source, branch string) bool {
@@ -371,7 +627,8 @@ func (localeFile *LocaleFile) shouldCreateLocale(source *Source, branch string)
 	return (localeFile.Name != "" || localeFile.Code != "")
 }
 
-func getUploadResult(client *phrase.APIClient, projectId string, upload *phrase.Upload, branch string) (result string, err error) {
+// getUploadResult waits until upload is processed and returns the processed upload.
+func getUploadResult(client *phrase.APIClient, projectId string, upload *phrase.Upload, branch string) (*phrase.Upload, error) {
 	b := &backoff.Backoff{
 		Min:    500 * time.Millisecond,
 		Max:    10 * time.Second,
@@ -379,19 +636,19 @@ func getUploadResult(client *phrase.APIClient, projectId string, upload *phrase.
 		Jitter: true,
 	}
 
-	for ; result != "success" && result != "error"; result = upload.State {
+	for upload.State != "success" && upload.State != "error" {
 		time.Sleep(b.Duration())
 		uploadShowOpts := phrase.UploadShowOpts{
 			Branch: optional.NewString(branch),
 		}
-		uploadhDetails, _, err := client.UploadsApi.UploadShow(Auth, projectId, upload.Id, &uploadShowOpts)
-		upload = &uploadhDetails
+		uploadDetails, _, err := client.UploadsApi.UploadShow(Auth, projectId, upload.Id, &uploadShowOpts)
 		if err != nil {
-			break
+			return upload, err
 		}
+		upload = &uploadDetails
 	}
 
-	return
+	return upload, nil
 }
 
 func getBranchCreateResult(client *phrase.APIClient, projectId string, branch *phrase.Branch) (result string, err error) {
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+)
+
+// PrintPushPlan prints the uploads a push would perform without creating any
+// branches, locales or uploads in Phrase.
+func (sources Sources) PrintPushPlan(branch string, tag string) error {
+	w := tabwriter.NewWriter(print.Out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tLOCALE ID\tLOCALE CODE\tLOCALE NAME\tCREATE LOCALE\tTAGS\tBRANCH\tFORMAT")
+
+	for _, source := range sources {
+		localeFiles, err := source.LocaleFiles()
+		if err != nil {
+			return err
+		}
+
+		for _, localeFile := range localeFiles {
+			event := plannedUploadEvent{
+				Event:        "planned_upload",
+				File:         localeFile.RelPath(),
+				LocaleID:     localeFile.ID,
+				LocaleCode:   localeFile.Code,
+				LocaleName:   localeFile.Name,
+				CreateLocale: source.plannedLocale(localeFile, branch),
+				Tags:         source.uploadTags(localeFile, tag),
+				Branch:       branch,
+				Format:       source.GetFileFormat(),
+			}
+			print.Event(event)
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				event.File,
+				orDash(event.LocaleID),
+				orDash(event.LocaleCode),
+				orDash(event.LocaleName),
+				orDash(event.CreateLocale),
+				orDash(event.Tags),
+				orDash(event.Branch),
+				event.Format,
+			)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(print.Out())
+	fmt.Fprintln(print.Out(), "Dry run: nothing was uploaded.")
+	return nil
+}
+
+// plannedLocale describes the locale that would be created for localeFile, or
+// returns an empty string if no locale would be created.
+func (source *Source) plannedLocale(localeFile *LocaleFile, branch string) string {
+	if !localeFile.shouldCreateLocale(source, branch) {
+		return ""
+	}
+
+	// localeCreateParams fills in missing codes, so work on a copy
+	lf := *localeFile
+	params := source.localeCreateParams(&lf, branch)
+
+	desc := []string{}
+	if params.Name != "" {
+		desc = append(desc, "name: "+params.Name)
+	}
+	if params.Code != "" {
+		desc = append(desc, "code: "+params.Code)
+	}
+	return strings.Join(desc, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
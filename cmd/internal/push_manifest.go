@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antihax/optional"
+	"github.com/phrase/phrase-go"
+)
+
+// checkUnchanged hashes the file of result and marks it as unchanged if it was
+// pushed to the same project and branch with the same content and parameters
+// before, unless cmd.Force is set.
+func (source *Source) checkUnchanged(result *pushResult, cmd *PushCommand) {
+	localeFile := result.localeFile
+	result.sha256 = fileSHA256(localeFile.Path)
+
+	if cmd.Force || cmd.lock == nil || localeFile.shouldCreateLocale(source, cmd.Branch) {
+		return
+	}
+
+	previous := cmd.lock.upload(localeFile.Path, source.ProjectID, cmd.Branch)
+	result.unchanged = previous != nil && previous.SHA256 == result.sha256 &&
+		previous.Params == source.uploadFingerprint(localeFile, cmd.Branch, cmd.Tag) &&
+		source.confirmUpload(localeFile, previous, cmd)
+}
+
+// confirmUpload returns true if the previous upload of localeFile is known to
+// have been processed successfully. Uploads which were not waited for are
+// looked up, and recorded as confirmed once they succeeded.
+func (source *Source) confirmUpload(localeFile *LocaleFile, previous *UploadState, cmd *PushCommand) bool {
+	if !previous.Unconfirmed {
+		return true
+	}
+
+	opts := phrase.UploadShowOpts{
+		Branch: optional.NewString(cmd.Branch),
+	}
+	upload, _, err := source.Client().UploadsApi.UploadShow(Auth, source.ProjectID, previous.UploadID, &opts)
+	if err != nil || upload.State != "success" {
+		// failed, still processing or unknown, so the file is uploaded again
+		return false
+	}
+
+	confirmed := *previous
+	confirmed.Unconfirmed = false
+	cmd.lock.setUpload(localeFile.Path, &confirmed)
+	return true
+}
+
+// recordPush adds every file of results which was uploaded to the lock file.
+// Uploads which were not waited for are recorded as unconfirmed, as they may
+// still fail to be processed.
+func (source *Source) recordPush(results []*pushResult, cmd *PushCommand) {
+	if cmd.lock == nil {
+		return
+	}
+
+	for _, result := range results {
+		if result.skipped || result.unchanged || result.failed() || result.upload == nil {
+			continue
+		}
+		if result.waited && result.upload.State != "success" {
+			continue
+		}
+
+		cmd.lock.setUpload(result.localeFile.Path, &UploadState{
+			ProjectID:   source.ProjectID,
+			Branch:      cmd.Branch,
+			Params:      source.uploadFingerprint(result.localeFile, cmd.Branch, cmd.Tag),
+			SHA256:      result.sha256,
+			UploadID:    result.upload.Id,
+			UploadedAt:  time.Now().UTC(),
+			Unconfirmed: !result.waited,
+		})
+	}
+}
+
+// uploadFingerprint returns a hash of the parameters localeFile is uploaded
+// with.
+func (source *Source) uploadFingerprint(localeFile *LocaleFile, branch string, tag string) string {
+	return sha256Hex([]byte(fmt.Sprintf("%+v", *source.uploadParams(localeFile, branch, tag))))
+}
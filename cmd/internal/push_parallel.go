@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-cli/cmd/internal/spinner"
+	"github.com/phrase/phrase-go"
+)
+
+// pushParallel uploads localeFiles using up to cmd.Parallel concurrent workers
+// and prints the results in the order of localeFiles once all uploads are done.
+func (source *Source) pushParallel(client *phrase.APIClient, localeFiles LocaleFiles, cmd *PushCommand) []*pushResult {
+	results := make([]*pushResult, len(localeFiles))
+	for i, localeFile := range localeFiles {
+		results[i] = &pushResult{localeFile: localeFile}
+		source.checkUnchanged(results[i], cmd)
+	}
+
+	// Locales are created before uploading, so that files sharing a locale do
+	// not race each other to create it.
+	for _, result := range results {
+		localeFile := result.localeFile
+		if !localeFile.shouldCreateLocale(source, cmd.Branch) {
+			continue
+		}
+
+		localeDetails, created, err := source.createLocale(client, localeFile, cmd.Branch)
+		if err != nil {
+			result.err = fmt.Errorf("failed to create locale: %s", err)
+			continue
+		}
+		localeFile.ID = localeDetails.Id
+		localeFile.Code = localeDetails.Code
+		localeFile.Name = localeDetails.Name
+		result.createdLocale = created
+	}
+
+	var failed int32
+	if anyFailed(results) {
+		failed = 1
+	}
+
+	jobs := make(chan *pushResult)
+
+	fmt.Fprintf(print.Out(), "Uploading %d files using %d workers... ", len(localeFiles), cmd.Parallel)
+	spinner.While(func() {
+		var wg sync.WaitGroup
+		for w := 0; w < cmd.Parallel; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for result := range jobs {
+					source.pushLocaleFile(client, result, cmd)
+					if result.failed() {
+						atomic.StoreInt32(&failed, 1)
+					}
+				}
+			}()
+		}
+
+		for _, result := range results {
+			if result.err != nil || result.unchanged {
+				continue
+			}
+			if cmd.FailFast && atomic.LoadInt32(&failed) == 1 {
+				result.skipped = true
+				continue
+			}
+			jobs <- result
+		}
+		close(jobs)
+		wg.Wait()
+	})
+	fmt.Fprintln(print.Out())
+
+	for _, result := range results {
+		result.print()
+		result.event()
+	}
+
+	return results
+}
+
+// pushLocaleFile uploads the locale file of result and, if requested, waits for
+// the upload to be processed.
+func (source *Source) pushLocaleFile(client *phrase.APIClient, result *pushResult, cmd *PushCommand) {
+	result.upload, result.err = source.uploadFile(client, result.localeFile, cmd.Branch, cmd.Tag)
+	if result.err != nil || !cmd.Wait {
+		return
+	}
+
+	result.upload, result.err = getUploadResult(client, source.ProjectID, result.upload, cmd.Branch)
+	result.waited = true
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+)
+
+// ExitCodePushFailed is the exit code of push if any file could not be
+// uploaded or processed.
+const ExitCodePushFailed = 3
+
+// PushFailedError is returned by push if any file could not be uploaded or
+// processed.
+type PushFailedError struct {
+	Failed int
+}
+
+func (err *PushFailedError) Error() string {
+	return fmt.Sprintf("%d file(s) could not be uploaded or processed", err.Failed)
+}
+
+func (err *PushFailedError) ExitCode() int {
+	return ExitCodePushFailed
+}
+
+// pushResult is the outcome of pushing a single locale file.
+type pushResult struct {
+	localeFile *LocaleFile
+	upload     *phrase.Upload
+	// waited is true if upload was polled until it was processed.
+	waited bool
+	// createdLocale is true if the remote locale was created for this file.
+	createdLocale bool
+	// skipped is true if the file was not uploaded at all.
+	skipped bool
+	// unchanged is true if the file was not uploaded, as it did not change
+	// since the last push.
+	unchanged bool
+	// sha256 is the hash of the file content when it was uploaded.
+	sha256 string
+	err    error
+}
+
+// failed returns true if the file could not be uploaded or processed.
+func (result *pushResult) failed() bool {
+	if result.err != nil {
+		return true
+	}
+	return result.waited && result.upload != nil && result.upload.State == "error"
+}
+
+func anyFailed(results []*pushResult) bool {
+	for _, result := range results {
+		if result.failed() {
+			return true
+		}
+	}
+	return false
+}
+
+func (result *pushResult) print() {
+	path := result.localeFile.RelPath()
+
+	switch {
+	case result.skipped:
+		fmt.Fprintf(print.Out(), "Skipped %s.\n", path)
+	case result.unchanged:
+		fmt.Fprintf(print.Out(), "Skipped %s, it did not change since the last push.\n", path)
+	case result.err != nil:
+		print.Failure("Failed to upload %s: %s", path, result.err)
+	case !result.waited:
+		fmt.Fprintf(print.Out(), "Uploaded %s. Check upload Id: %s, filename: %s for information about processing results.\n", path, result.upload.Id, result.upload.Filename)
+	case result.upload.State == "success":
+		print.Success("Successfully uploaded and processed %s.", path)
+	case result.upload.State == "error":
+		print.Failure("There was an error processing %s. Your changes were not saved online.", path)
+	}
+}
+
+func (result *pushResult) event() {
+	event := uploadEvent{
+		Event:         "upload",
+		File:          result.localeFile.RelPath(),
+		LocaleID:      result.localeFile.ID,
+		LocaleCode:    result.localeFile.Code,
+		LocaleName:    result.localeFile.Name,
+		CreatedLocale: result.createdLocale,
+		Skipped:       result.skipped,
+		Unchanged:     result.unchanged,
+	}
+
+	if result.upload != nil {
+		event.UploadID = result.upload.Id
+		event.State = result.upload.State
+		if result.waited {
+			event.Summary = &result.upload.Summary
+		}
+	}
+
+	if result.err != nil {
+		event.Error = result.err.Error()
+	}
+
+	print.Event(event)
+}
+
+// pushSummary counts the outcomes of all files of a push.
+type pushSummary struct {
+	Event          string `json:"event"`
+	Succeeded      int    `json:"succeeded"`
+	Failed         int    `json:"failed"`
+	LocalesCreated int    `json:"locales_created"`
+	Skipped        int    `json:"skipped"`
+	Unchanged      int    `json:"unchanged"`
+}
+
+func (summary *pushSummary) add(results []*pushResult) {
+	for _, result := range results {
+		switch {
+		case result.skipped:
+			summary.Skipped++
+		case result.unchanged:
+			summary.Unchanged++
+		case result.failed():
+			summary.Failed++
+		default:
+			summary.Succeeded++
+		}
+
+		if result.createdLocale {
+			summary.LocalesCreated++
+		}
+	}
+}
+
+func (summary *pushSummary) print() {
+	summary.Event = "summary"
+	print.Event(summary)
+
+	fmt.Fprintln(print.Out())
+	w := tabwriter.NewWriter(print.Out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Succeeded:\t%d\n", summary.Succeeded)
+	fmt.Fprintf(w, "Failed:\t%d\n", summary.Failed)
+	fmt.Fprintf(w, "Locales created:\t%d\n", summary.LocalesCreated)
+	fmt.Fprintf(w, "Skipped:\t%d\n", summary.Skipped)
+	fmt.Fprintf(w, "Unchanged:\t%d\n", summary.Unchanged)
+	w.Flush()
+}
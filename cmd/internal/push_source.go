@@ -7,43 +7,141 @@ import (
 	"strings"
 
 	"github.com/antihax/optional"
+	"github.com/phrase/phrase-cli/cmd/internal/interpolate"
 	"github.com/phrase/phrase-cli/cmd/internal/paths"
+	"github.com/phrase/phrase-cli/cmd/internal/placeholders"
 	"github.com/phrase/phrase-go"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
-func SourcesFromConfig(config phrase.Config) (Sources, error) {
+// PushConfig contains the settings of the push section of a config file.
+type PushConfig struct {
+	Sources  Sources `json:"sources"`
+	Parallel int     `json:"parallel"`
+	// StrictLocales refuses to upload files which do not match a single
+	// remote locale exactly. It is enabled unless set to false.
+	StrictLocales *bool         `json:"strict_locales"`
+	LocaleMapping LocaleMapping `json:"locale_mapping"`
+}
+
+func PushConfigFromConfig(config phrase.Config) (*PushConfig, error) {
 	if config.Sources == nil || len(config.Sources) == 0 {
 		return nil, fmt.Errorf("no sources for upload specified")
 	}
 
-	tmp := struct {
-		Sources Sources
-	}{}
+	pushConfig := new(PushConfig)
+
+	raw, err := expandSection("push", config.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, excludes, err := splitFileExcludes(raw)
+	if err != nil {
+		return nil, err
+	}
 
 	sources := viper.New()
 	sources.SetConfigType("yaml")
-	err := sources.ReadConfig(bytes.NewReader(config.Sources))
+	err = sources.ReadConfig(bytes.NewReader(raw))
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = sources.UnmarshalExact(&tmp, ViperStructTag())
+	err = sources.UnmarshalExact(pushConfig, ViperStructTag())
 	if err != nil {
 		return nil, err
 	}
 
-	srcs := tmp.Sources
+	pushConfig.LocaleMapping, err = sectionLocaleMapping(raw)
+	if err != nil {
+		return nil, err
+	}
 
-	projectId := config.DefaultProjectID
+	for i, patterns := range excludes {
+		if i < len(pushConfig.Sources) && pushConfig.Sources[i] != nil {
+			pushConfig.Sources[i].excludes = patterns
+		}
+	}
+
+	return pushConfig, nil
+}
+
+// splitFileExcludes replaces file lists of the sources in the push section raw
+// by their single pattern without a leading !, e.g.
+//
+//	file:
+//	  - ./packages/*/src/**/i18n/<locale_code>.json
+//	  - "!**/node_modules"
+//
+// and returns the patterns with a leading ! by index of the source.
+func splitFileExcludes(raw []byte) ([]byte, map[int][]string, error) {
+	var section map[interface{}]interface{}
+	if err := yaml.Unmarshal(raw, &section); err != nil {
+		return nil, nil, err
+	}
+
+	sources, _ := section["sources"].([]interface{})
+	excludes := map[int][]string{}
+	changed := false
+	for i, source := range sources {
+		source, _ := source.(map[interface{}]interface{})
+		list, ok := source["file"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		var file string
+		for _, item := range list {
+			pattern, ok := item.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("file of source %d must be a pattern or a list of patterns", i+1)
+			}
+			switch {
+			case strings.HasPrefix(pattern, "!"):
+				excludes[i] = append(excludes[i], strings.TrimPrefix(pattern, "!"))
+			case file != "":
+				return nil, nil, fmt.Errorf("file of source %d may only contain one pattern without a leading !", i+1)
+			default:
+				file = pattern
+			}
+		}
+		source["file"] = file
+		changed = true
+	}
+
+	if !changed {
+		return raw, excludes, nil
+	}
+
+	raw, err := yaml.Marshal(section)
+	return raw, excludes, err
+}
+
+func SourcesFromConfig(config phrase.Config) (Sources, error) {
+	pushConfig, err := PushConfigFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	srcs := pushConfig.Sources
+
+	projectId, err := interpolate.Env(config.DefaultProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("project_id: %s", err)
+	}
 	fileFormat := config.DefaultFileFormat
+	strictLocales := pushConfig.StrictLocales == nil || *pushConfig.StrictLocales
 
 	validSources := []*Source{}
 	for _, source := range srcs {
 		if source == nil {
 			continue
 		}
+		source.strictLocales = strictLocales
+		source.LocaleMapping = pushConfig.LocaleMapping.merge(source.LocaleMapping)
 		if source.ProjectID == "" {
 			source.ProjectID = projectId
 		}
@@ -81,19 +179,39 @@ func (sources Sources) Validate() error {
 }
 
 type Source struct {
+	Name        string                   `json:"name"`
 	File        string                   `json:"file"`
 	ProjectID   string                   `json:"project_id"`
 	Branch      string                   `json:"branch"`
+	Host        string                   `json:"host"`
 	AccessToken string                   `json:"access_token"`
 	FileFormat  string                   `json:"file_format"`
 	Params      *phrase.UploadCreateOpts `json:"params,omitempty"`
+	// LocaleMapping maps the locale codes or names in file paths to the ids,
+	// names or codes of remote locales.
+	LocaleMapping LocaleMapping `json:"locale_mapping"`
+	// Exclude lists patterns of files which are not pushed, in addition to
+	// the ones ignored by the ignore file.
+	Exclude []string `json:"exclude"`
 
 	RemoteLocales []*phrase.Locale
 	Format        *phrase.Format
+
+	// excludes are the patterns given with a leading ! in a file list.
+	excludes []string
+	// strictLocales refuses files which do not match a single remote locale.
+	strictLocales bool
+	// branch is the branch RemoteLocales belong to.
+	branch string
+
+	// selection narrows the locale files of the source if it is set.
+	selection *Selection
+	// warned holds the paths of the files skipped with a warning.
+	warned map[string]bool
 }
 
 func (source *Source) GetLocaleID() string {
-	if source.Params != nil && !source.Params.LocaleId.IsSet() {
+	if source.Params != nil && source.Params.LocaleId.IsSet() {
 		return source.Params.LocaleId.Value()
 	}
 	return ""
@@ -121,26 +239,42 @@ func (source *Source) CheckPreconditions() error {
 		}
 	}
 
-	starCount := strings.Count(source.File, "*")
-	recCount := strings.Count(source.File, "**")
-
-	// starCount contains the `**` so that must be taken into account.
-	if starCount-(recCount*2) > 1 {
-		duplicatedPlaceholders = append(duplicatedPlaceholders, "*")
-	}
-
-	if recCount > 1 {
-		duplicatedPlaceholders = append(duplicatedPlaceholders, "**")
-	}
-
 	if len(duplicatedPlaceholders) > 0 {
 		dups := strings.Join(duplicatedPlaceholders, ", ")
 		return fmt.Errorf(fmt.Sprintf("%s can only occur once in a file pattern!", dups))
 	}
 
+	if err := paths.ValidatePattern(placeholders.ToGlobbingPattern(source.File)); err != nil {
+		return err
+	}
+	for _, exclude := range source.excludePatterns() {
+		if err := paths.ValidatePattern(exclude); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// excludePatterns returns the patterns of files the source does not push.
+func (source *Source) excludePatterns() []string {
+	return append(append([]string{}, source.excludes...), source.Exclude...)
+}
+
+// Client returns the API client for the host and access token of source.
+func (source *Source) Client() *phrase.APIClient {
+	return clientFor(source.Host, source.AccessToken)
+}
+
+func (sources Sources) ClientForProject(projectId string) *phrase.APIClient {
+	for _, source := range sources {
+		if source.ProjectID == projectId {
+			return source.Client()
+		}
+	}
+	return newClient()
+}
+
 func (sources Sources) ProjectIds() []string {
 	projectIds := []string{}
 	for _, source := range sources {
@@ -154,55 +288,93 @@ func (source *Source) uploadFile(client *phrase.APIClient, localeFile *LocaleFil
 		fmt.Fprintln(os.Stdout, "Actual file location:", localeFile.Path)
 	}
 
-	params := new(phrase.UploadCreateOpts)
-	*params = *source.Params
+	params := source.uploadParams(localeFile, branch, tag)
 
-	var err error
 	file, err := os.Open(localeFile.Path)
-	params.File = optional.NewInterface(file)
-
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
+	params.File = optional.NewInterface(file)
 
-	if !params.LocaleId.IsSet() {
-		switch {
-		case localeFile.ID != "":
-			params.LocaleId = optional.NewString(localeFile.ID)
-		case localeFile.Code != "":
-			params.LocaleId = optional.NewString(localeFile.Code)
-		}
-	}
-	if localeFile.Tag != "" {
-		var v string
-		if params.Tags.IsSet() {
-			v = params.Tags.Value() + ","
-		}
-		v += localeFile.Tag
-		params.Tags = optional.NewString(v)
-	} 
+	upload, _, err := client.UploadsApi.UploadCreate(Auth, source.ProjectID, params)
 
-    if tag != "" {
-        params.Tags = optional.NewString(tag)
-    }
+	return &upload, err
+}
+
+// uploadParams returns the parameters localeFile is uploaded with, except for
+// the file itself.
+func (source *Source) uploadParams(localeFile *LocaleFile, branch string, tag string) *phrase.UploadCreateOpts {
+	params := new(phrase.UploadCreateOpts)
+	*params = *source.Params
+
+	switch {
+	case params.LocaleId.IsSet() && !placeholders.ContainsLocalePlaceholder(params.LocaleId.Value()):
+	case localeFile.ID != "":
+		params.LocaleId = optional.NewString(localeFile.ID)
+	case source.replacePlaceholderInParams(localeFile) != "":
+		params.LocaleId = optional.NewString(source.replacePlaceholderInParams(localeFile))
+	case localeFile.Code != "":
+		params.LocaleId = optional.NewString(localeFile.Code)
+	default:
+		// a placeholder which cannot be resolved must not be sent
+		params.LocaleId = optional.String{}
+	}
+	if tags := source.uploadTags(localeFile, tag); tags != "" {
+		params.Tags = optional.NewString(tags)
+	}
 
 	if branch != "" {
 		params.Branch = optional.NewString(branch)
 	}
 
-	upload, _, err := client.UploadsApi.UploadCreate(Auth, source.ProjectID, params)
+	return params
+}
 
-	return &upload, err
+// uploadTags returns the tags an upload of localeFile is created with. A tag
+// given on the command line replaces all tags from the config.
+func (source *Source) uploadTags(localeFile *LocaleFile, tag string) string {
+	if tag != "" {
+		return tag
+	}
+
+	var tags string
+	if source.Params != nil && source.Params.Tags.IsSet() {
+		tags = source.Params.Tags.Value()
+	}
+	if localeFile.Tag != "" {
+		if tags != "" {
+			tags += ","
+		}
+		tags += localeFile.Tag
+	}
+	return tags
 }
 
-func (source *Source) createLocale(client *phrase.APIClient, localeFile *LocaleFile, branch string) (*phrase.LocaleDetails, error) {
+// createLocale creates the remote locale for localeFile, unless it already
+// exists. created is false if an existing locale is returned.
+func (source *Source) createLocale(client *phrase.APIClient, localeFile *LocaleFile, branch string) (localeDetails *phrase.LocaleDetails, created bool, err error) {
 	localeDetails, found, err := source.getLocaleIfExist(client, localeFile, branch)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	} else if found {
-		return localeDetails, nil
+		return localeDetails, false, nil
+	}
+
+	localeParams := source.localeCreateParams(localeFile, branch)
+
+	localeDetailsData, _, err := client.LocalesApi.LocaleCreate(Auth, source.ProjectID, *localeParams, &phrase.LocaleCreateOpts{})
+
+	if err != nil {
+		return nil, false, err
 	}
 
+	return &localeDetailsData, true, nil
+}
+
+// localeCreateParams returns the parameters used to create the remote locale
+// for localeFile. If localeFile has no code, its name is used as code.
+func (source *Source) localeCreateParams(localeFile *LocaleFile, branch string) *phrase.LocaleCreateParameters {
 	localeParams := new(phrase.LocaleCreateParameters)
 
 	if localeFile.Name != "" {
@@ -228,13 +400,7 @@ func (source *Source) createLocale(client *phrase.APIClient, localeFile *LocaleF
 		localeParams.Branch = branch
 	}
 
-	localeDetailsData, _, err := client.LocalesApi.LocaleCreate(Auth, source.ProjectID, *localeParams, &phrase.LocaleCreateOpts{})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &localeDetailsData, nil
+	return localeParams
 }
 
 func (source *Source) getLocaleIfExist(client *phrase.APIClient, localeFile *LocaleFile, branch string) (*phrase.LocaleDetails, bool, error) {
@@ -246,9 +412,12 @@ func (source *Source) getLocaleIfExist(client *phrase.APIClient, localeFile *Loc
 	localeShowParams := &phrase.LocaleShowOpts{
 		Branch: optional.NewString(branch),
 	}
-	localeDetail, _, err := client.LocalesApi.LocaleShow(Auth, source.ProjectID, identifier, localeShowParams)
+	localeDetail, response, err := client.LocalesApi.LocaleShow(Auth, source.ProjectID, identifier, localeShowParams)
 
 	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			return nil, false, nil
+		}
 		return nil, false, err
 	}
 
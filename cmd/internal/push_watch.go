@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/phrase/phrase-cli/cmd/internal/paths"
+	"github.com/phrase/phrase-cli/cmd/internal/placeholders"
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+)
+
+// watchDebounce is how long a file must stay unchanged after an event before
+// it is uploaded, so that editors writing a file in several steps cause a
+// single upload.
+const watchDebounce = 500 * time.Millisecond
+
+type watchedFile struct {
+	source     *Source
+	localeFile *LocaleFile
+}
+
+// watch uploads locale files of sources again whenever they change, until the
+// process is interrupted. Files and directories created later are picked up
+// if they match the source patterns.
+func (cmd *PushCommand) watch(sources Sources) error {
+	files, err := watchedFiles(sources, nil)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Directories are watched instead of the files themselves, as many editors
+	// save by replacing the file, which ends a watch on the file. All
+	// directories the patterns are searched in are watched, so that new files
+	// are noticed as well.
+	for _, source := range sources {
+		pattern, err := source.filePattern()
+		if err != nil {
+			return err
+		}
+
+		root, depth := paths.WalkRoot(placeholders.ToGlobbingPattern(pattern))
+		if depth == 0 {
+			root, depth = filepath.Dir(root), 1
+		}
+		if err := watchDirs(watcher, root, depth); err != nil {
+			return err
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	fmt.Fprintf(print.Out(), "\nWatching %d files and new files of the sources for changes. Press Ctrl+C to stop.\n", len(files))
+
+	pending := map[string]bool{}
+	rescan := false
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			path, err := filepath.Abs(event.Name)
+			if err != nil {
+				continue
+			}
+
+			if event.Op&fsnotify.Create != 0 && paths.IsDir(path) {
+				// files may be created in the directory before it is watched
+				if err := watchDirs(watcher, path, -1); err != nil {
+					print.Error(err)
+				}
+				rescan = true
+			} else if _, ok := files[path]; ok {
+				pending[path] = true
+			} else {
+				rescan = true
+			}
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			print.Error(err)
+		case <-debounce.C:
+			if rescan {
+				rescanned, err := watchedFiles(sources, files)
+				if err != nil {
+					print.Error(err)
+				} else {
+					for path := range rescanned {
+						if _, ok := files[path]; !ok {
+							pending[path] = true
+						}
+					}
+					files = rescanned
+				}
+				rescan = false
+			}
+
+			for path := range pending {
+				if file, ok := files[path]; ok {
+					file.source.pushChanged(file.localeFile, cmd)
+				}
+			}
+			pending = map[string]bool{}
+		case <-interrupt:
+			return nil
+		}
+	}
+}
+
+// watchedFiles returns the locale files of sources by path. The files of
+// previous are kept, as they may have been updated while pushing.
+func watchedFiles(sources Sources, previous map[string]*watchedFile) (map[string]*watchedFile, error) {
+	files := map[string]*watchedFile{}
+	for _, source := range sources {
+		localeFiles, err := source.LocaleFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, localeFile := range localeFiles {
+			if file, ok := previous[localeFile.Path]; ok {
+				files[localeFile.Path] = file
+				continue
+			}
+			files[localeFile.Path] = &watchedFile{source: source, localeFile: localeFile}
+		}
+	}
+	return files, nil
+}
+
+// watchDirs adds dir and its subdirectories up to depth levels below it to
+// watcher. A negative depth adds all of them.
+func watchDirs(watcher *fsnotify.Watcher, dir string, depth int) error {
+	if !paths.IsDir(dir) {
+		// the directory may be created later, which is noticed if its parent
+		// is watched
+		return nil
+	}
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+	if depth == 1 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := watchDirs(watcher, filepath.Join(dir, entry.Name()), depth-1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pushChanged uploads a single locale file which changed while watching.
+func (source *Source) pushChanged(localeFile *LocaleFile, cmd *PushCommand) {
+	client := source.Client()
+	result := &pushResult{localeFile: localeFile}
+
+	source.checkUnchanged(result, cmd)
+	if result.unchanged {
+		result.print()
+		result.event()
+		return
+	}
+
+	if localeFile.shouldCreateLocale(source, cmd.Branch) {
+		localeDetails, created, err := source.createLocale(client, localeFile, cmd.Branch)
+		if err != nil {
+			result.err = fmt.Errorf("failed to create locale: %s", err)
+			result.print()
+			result.event()
+			return
+		}
+		localeFile.ID = localeDetails.Id
+		localeFile.Code = localeDetails.Code
+		localeFile.Name = localeDetails.Name
+		localeFile.ExistsRemote = true
+		result.createdLocale = created
+	}
+
+	source.pushLocaleFile(client, result, cmd)
+	result.print()
+	result.event()
+
+	source.recordPush([]*pushResult{result}, cmd)
+	if cmd.lock != nil {
+		if err := cmd.lock.Save(); err != nil {
+			print.Error(err)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+const (
+	headerRateRemaining = "X-Rate-Limit-Remaining"
+	headerRateReset     = "X-Rate-Limit-Reset"
+	headerRetryAfter    = "Retry-After"
+)
+
+// DefaultMaxWait is the default for the total time a single request may spend
+// waiting for the rate limit and for retries.
+const DefaultMaxWait = 5 * time.Minute
+
+// DefaultTransport is shared by all API clients, so that they draw from the
+// same rate limit budget.
+var DefaultTransport = &Transport{MaxWait: DefaultMaxWait}
+
+// Transport is an http.RoundTripper that keeps track of the API rate limit and
+// retries failed requests.
+//
+// Before sending a request, Transport waits until the rate limit is reset if
+// a previous response reported that no requests are left. Requests answered
+// with 429 are retried after the time given in the Retry-After header or when
+// the rate limit is reset. Server errors and network errors are retried with
+// an exponential backoff, but only for requests which are safe to repeat;
+// other requests are only retried on 429 and 503, which the API sends without
+// having processed the request. MaxWait caps the total time spent waiting for
+// a single request.
+type Transport struct {
+	// Base is used to send requests, http.DefaultTransport if nil.
+	Base    http.RoundTripper
+	MaxWait time.Duration
+
+	mu     sync.Mutex
+	limits map[string]*limit
+}
+
+// limit is the rate limit state of a single host and set of credentials.
+type limit struct {
+	remaining int
+	reset     time.Time
+	announced time.Time
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := &backoff.Backoff{
+		Min:    500 * time.Millisecond,
+		Max:    10 * time.Second,
+		Factor: 2,
+		Jitter: true,
+	}
+
+	key := req.URL.Host + " " + req.Header.Get("Authorization")
+	var waited time.Duration
+
+	for attempt := 0; ; attempt++ {
+		if delay := t.delayUntilReset(key); delay > 0 {
+			if waited+delay > t.MaxWait {
+				return nil, fmt.Errorf("rate limit exceeded: next request possible in %s, which exceeds the maximum wait of %s", delay.Round(time.Second), t.MaxWait)
+			}
+			if err := sleep(req, delay); err != nil {
+				return nil, err
+			}
+			waited += delay
+		}
+
+		if attempt > 0 {
+			var err error
+			req, err = rewind(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base().RoundTrip(req)
+		if err == nil {
+			t.update(key, resp.Header)
+		}
+
+		delay, retry := t.retryDelay(req, resp, err, b)
+		if !retry || waited+delay > t.MaxWait || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+		waited += delay
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// retryDelay reports whether the request should be sent again and how long to
+// wait before doing so.
+func (t *Transport) retryDelay(req *http.Request, resp *http.Response, err error, b *backoff.Backoff) (time.Duration, bool) {
+	if req.Body != nil && req.GetBody == nil {
+		// the body was consumed and cannot be sent again
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		return b.Duration(), isIdempotent(req.Method)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if delay, ok := retryAfter(resp.Header); ok {
+			return delay, true
+		}
+		if reset, ok := parseReset(resp.Header); ok && time.Until(reset) > 0 {
+			return time.Until(reset), true
+		}
+		return b.Duration(), true
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		return b.Duration(), true
+	case resp.StatusCode >= 500:
+		return b.Duration(), isIdempotent(req.Method)
+	default:
+		return 0, false
+	}
+}
+
+// delayUntilReset returns how long to wait before the next request with key
+// may be sent.
+func (t *Transport) delayUntilReset(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.limits[key]
+	if !ok || l.remaining > 0 {
+		return 0
+	}
+
+	delay := time.Until(l.reset)
+	if delay <= 0 {
+		return 0
+	}
+
+	if !l.announced.Equal(l.reset) && delay > time.Second {
+		fmt.Fprintf(os.Stderr, "Rate limit exceeded. Requests will resume in %d seconds\n", int64(delay.Seconds()))
+		l.announced = l.reset
+	}
+
+	return delay
+}
+
+// update records the rate limit reported in header.
+func (t *Transport) update(key string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get(headerRateRemaining))
+	if err != nil {
+		return
+	}
+	reset, _ := parseReset(header)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.limits == nil {
+		t.limits = map[string]*limit{}
+	}
+	l, ok := t.limits[key]
+	if !ok {
+		l = &limit{}
+		t.limits[key] = l
+	}
+	l.remaining = remaining
+	l.reset = reset
+}
+
+func parseReset(header http.Header) (time.Time, bool) {
+	reset, err := strconv.ParseInt(header.Get(headerRateReset), 10, 64)
+	if err != nil || reset == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), true
+}
+
+// retryAfter parses the Retry-After header, which either contains a number of
+// seconds or an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get(headerRetryAfter)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// rewind returns a copy of req with a fresh body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// sleep waits for d or until the request is cancelled.
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
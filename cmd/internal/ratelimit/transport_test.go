@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testServer(statuses []int, header http.Header) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		status := statuses[len(statuses)-1]
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		w.WriteHeader(status)
+	}))
+	return server, &calls
+}
+
+func TestTransport_retries(t *testing.T) {
+	tests := []struct {
+		method   string
+		statuses []int
+		header   http.Header
+		calls    int
+		status   int
+	}{
+		{
+			method:   http.MethodGet,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"0"}},
+			calls:    2,
+			status:   http.StatusOK,
+		}, {
+			method:   http.MethodPost,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"0"}},
+			calls:    2,
+			status:   http.StatusOK,
+		}, {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusBadGateway, http.StatusOK},
+			calls:    2,
+			status:   http.StatusOK,
+		}, {
+			method:   http.MethodPost,
+			statuses: []int{http.StatusBadGateway, http.StatusOK},
+			calls:    1,
+			status:   http.StatusBadGateway,
+		}, {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusNotFound},
+			calls:    1,
+			status:   http.StatusNotFound,
+		}, {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusTooManyRequests},
+			header:   http.Header{"Retry-After": {"60"}},
+			calls:    1,
+			status:   http.StatusTooManyRequests,
+		},
+	}
+
+	for _, test := range tests {
+		server, calls := testServer(test.statuses, test.header)
+		client := &http.Client{Transport: &Transport{MaxWait: 5 * time.Second}}
+
+		req, _ := http.NewRequest(test.method, server.URL, strings.NewReader("body"))
+		resp, err := client.Do(req)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%s %v: unexpected error %s", test.method, test.statuses, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if *calls != test.calls {
+			t.Errorf("%s %v: expected %d calls, got %d", test.method, test.statuses, test.calls, *calls)
+		}
+
+		if resp.StatusCode != test.status {
+			t.Errorf("%s %v: expected status %d, got %d", test.method, test.statuses, test.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestTransport_exhaustedRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server, calls := testServer([]int{http.StatusOK}, http.Header{
+		"X-Rate-Limit-Remaining": {"0"},
+		"X-Rate-Limit-Reset":     {strconv.FormatInt(reset, 10)},
+	})
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{MaxWait: time.Second}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	_, err = client.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
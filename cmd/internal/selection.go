@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selection narrows sources and targets to the ones with the given names,
+// and their locale files to the given locales and tags. Each list holds
+// alternatives, an empty list selects everything.
+type Selection struct {
+	Names   []string
+	Locales []string
+	Tags    []string
+}
+
+// NewSelection returns the selection for comma separated lists of names,
+// locales and tags as given on the command line.
+func NewSelection(names, locales, tags string) *Selection {
+	return &Selection{
+		Names:   splitList(names),
+		Locales: splitList(locales),
+		Tags:    splitList(tags),
+	}
+}
+
+func splitList(list string) []string {
+	values := []string{}
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// filtersFiles returns true if the selection narrows the locale files.
+func (selection *Selection) filtersFiles() bool {
+	return selection != nil && (len(selection.Locales) > 0 || len(selection.Tags) > 0)
+}
+
+// SelectSources returns the sources with the selected names and makes them
+// produce only the selected locale files.
+func (selection *Selection) SelectSources(sources Sources) (Sources, error) {
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = source.Name
+	}
+
+	selected := Sources{}
+	for _, i := range selection.selectNames(names) {
+		sources[i].selection = selection
+		selected = append(selected, sources[i])
+	}
+
+	if err := selection.checkNames("source", names); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// SelectTargets returns the targets with the selected names and makes them
+// produce only the selected locale files.
+func (selection *Selection) SelectTargets(targets Targets) (Targets, error) {
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.Name
+	}
+
+	selected := Targets{}
+	for _, i := range selection.selectNames(names) {
+		targets[i].selection = selection
+		selected = append(selected, targets[i])
+	}
+
+	if err := selection.checkNames("target", names); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// selectNames returns the indexes of the selected names.
+func (selection *Selection) selectNames(names []string) []int {
+	indexes := []int{}
+	for i, name := range names {
+		if len(selection.Names) == 0 || contains(selection.Names, name) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// checkNames returns an error if a selected name is not one of names.
+func (selection *Selection) checkNames(kind string, names []string) error {
+	for _, name := range selection.Names {
+		if !contains(names, name) {
+			return fmt.Errorf("there is no %s named %q", kind, name)
+		}
+	}
+	return nil
+}
+
+// filter returns the selected locale files. tags returns the tags of a
+// locale file.
+func (selection *Selection) filter(localeFiles LocaleFiles, tags func(*LocaleFile) []string) LocaleFiles {
+	if !selection.filtersFiles() {
+		return localeFiles
+	}
+
+	selected := LocaleFiles{}
+	for _, localeFile := range localeFiles {
+		if selection.matchesLocale(localeFile) && selection.matchesTags(tags(localeFile)) {
+			selected = append(selected, localeFile)
+		}
+	}
+	return selected
+}
+
+func (selection *Selection) matchesLocale(localeFile *LocaleFile) bool {
+	if len(selection.Locales) == 0 {
+		return true
+	}
+
+	for _, locale := range selection.Locales {
+		if locale == localeFile.Name || locale == localeFile.Code || locale == localeFile.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func (selection *Selection) matchesTags(tags []string) bool {
+	if len(selection.Tags) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		if contains(selection.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// errNoSelectedFiles is returned if a selection of locales or tags does not
+// match any locale file.
+func (selection *Selection) errNoSelectedFiles() error {
+	filters := []string{}
+	if len(selection.Locales) > 0 {
+		filters = append(filters, fmt.Sprintf("locale %s", strings.Join(selection.Locales, ", ")))
+	}
+	if len(selection.Tags) > 0 {
+		filters = append(filters, fmt.Sprintf("tag %s", strings.Join(selection.Tags, ", ")))
+	}
+	return fmt.Errorf("no locale files match the selected %s", strings.Join(filters, " and "))
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
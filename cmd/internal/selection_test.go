@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestNewSelection(t *testing.T) {
+	selection := NewSelection(" web, ,app", "", "a,b")
+
+	if len(selection.Names) != 2 || selection.Names[0] != "web" || selection.Names[1] != "app" {
+		t.Errorf("expected names [web app], got %v", selection.Names)
+	}
+	if len(selection.Locales) != 0 {
+		t.Errorf("expected no locales, got %v", selection.Locales)
+	}
+	if len(selection.Tags) != 2 {
+		t.Errorf("expected tags [a b], got %v", selection.Tags)
+	}
+}
+
+func TestSelection_SelectSources(t *testing.T) {
+	sources := Sources{{Name: "web"}, {Name: "app"}, {}}
+
+	selected, err := NewSelection("app", "", "").SelectSources(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 1 || selected[0].Name != "app" {
+		t.Errorf("expected source app, got %v", selected)
+	}
+
+	selected, err = NewSelection("", "", "").SelectSources(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 3 {
+		t.Errorf("expected all sources without names, got %v", selected)
+	}
+
+	if _, err := NewSelection("web,api", "", "").SelectSources(sources); err == nil || err.Error() != `there is no source named "api"` {
+		t.Errorf("expected an error for an unknown name, got %v", err)
+	}
+}
+
+func TestSelection_SelectTargets(t *testing.T) {
+	targets := Targets{{Name: "web"}, {Name: "app"}}
+
+	selected, err := NewSelection("web", "", "").SelectTargets(targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 1 || selected[0].Name != "web" {
+		t.Errorf("expected target web, got %v", selected)
+	}
+
+	if _, err := NewSelection("api", "", "").SelectTargets(targets); err == nil || err.Error() != `there is no target named "api"` {
+		t.Errorf("expected an error for an unknown name, got %v", err)
+	}
+}
+
+func TestSelection_filter(t *testing.T) {
+	localeFiles := LocaleFiles{
+		{Path: "en.json", ID: "l-en", Name: "English", Code: "en", Tag: "web"},
+		{Path: "de.json", ID: "l-de", Name: "German", Code: "de", Tag: "app"},
+		{Path: "fr.json", ID: "l-fr", Name: "French", Code: "fr"},
+	}
+	tags := func(localeFile *LocaleFile) []string {
+		return []string{localeFile.Tag}
+	}
+
+	tests := []struct {
+		locales, tags string
+		expected      []string
+	}{
+		{"", "", []string{"en.json", "de.json", "fr.json"}},
+		{"en", "", []string{"en.json"}},
+		{"German,l-fr", "", []string{"de.json", "fr.json"}},
+		{"", "app,other", []string{"de.json"}},
+		{"en,de", "app", []string{"de.json"}},
+		{"en", "app", []string{}},
+		{"es", "", []string{}},
+	}
+
+	for _, test := range tests {
+		selection := NewSelection("", test.locales, test.tags)
+		selected := selection.filter(localeFiles, tags)
+
+		paths := []string{}
+		for _, localeFile := range selected {
+			paths = append(paths, localeFile.Path)
+		}
+		if !equalStrings(paths, test.expected) {
+			t.Errorf("locales %q, tags %q: expected %v, got %v", test.locales, test.tags, test.expected, paths)
+		}
+	}
+
+	var none *Selection
+	if selected := none.filter(localeFiles, tags); len(selected) != len(localeFiles) {
+		t.Errorf("expected no selection to keep all files, got %v", selected)
+	}
+}
+
+func TestSelection_errNoSelectedFiles(t *testing.T) {
+	tests := []struct {
+		locales, tags string
+		expected      string
+	}{
+		{"en,de", "", "no locale files match the selected locale en, de"},
+		{"", "web", "no locale files match the selected tag web"},
+		{"en", "web,app", "no locale files match the selected locale en and tag web, app"},
+	}
+
+	for _, test := range tests {
+		selection := NewSelection("", test.locales, test.tags)
+		if !selection.filtersFiles() {
+			t.Errorf("locales %q, tags %q: expected the selection to filter files", test.locales, test.tags)
+		}
+		if err := selection.errNoSelectedFiles(); err.Error() != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, err.Error())
+		}
+	}
+
+	if NewSelection("web", "", "").filtersFiles() {
+		t.Errorf("expected a selection of names not to filter files")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -13,6 +13,7 @@ var Debug bool
 
 type ProjectLocales interface {
 	ProjectIds() []string
+	ClientForProject(projectId string) *phrase.APIClient
 }
 
 type LocaleCacheKey struct {
@@ -22,7 +23,7 @@ type LocaleCacheKey struct {
 
 type LocaleCache map[LocaleCacheKey][]*phrase.Locale
 
-func LocalesForProjects(client *phrase.APIClient, projectLocales ProjectLocales, branch string) (LocaleCache, error) {
+func LocalesForProjects(projectLocales ProjectLocales, branch string) (LocaleCache, error) {
 	projectIdToLocales := LocaleCache{}
 
 	for _, pid := range projectLocales.ProjectIds() {
@@ -33,7 +34,7 @@ func LocalesForProjects(client *phrase.APIClient, projectLocales ProjectLocales,
 
 		if _, ok := projectIdToLocales[key]; !ok {
 
-			remoteLocales, http_response, err := RemoteLocales(client, key)
+			remoteLocales, http_response, err := RemoteLocales(projectLocales.ClientForProject(pid), key)
 			if err != nil {
 				if http_response.StatusCode == 404 && branch != "" {
 					// skip this key if we targeted a branch in
@@ -5,8 +5,16 @@ import (
 	"time"
 )
 
+// Disabled turns While into a plain call of f, e.g. for machine-readable output.
+var Disabled bool
+
 // While executes f, displays an animated spinner while f runs, and stops when f returns.
 func While(f func()) {
+	if Disabled {
+		f()
+		return
+	}
+
 	c := make(chan struct{})
 
 	go func(c chan<- struct{}) {
@@ -0,0 +1,379 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/phrase/phrase-cli/cmd/internal/keys"
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-go"
+)
+
+const (
+	StatusInSync           = "in sync"
+	StatusLocallyModified  = "locally modified"
+	StatusRemotelyModified = "remotely modified"
+	StatusModifiedBoth     = "modified locally and remotely"
+	StatusMissingLocally   = "missing locally"
+	StatusMissingRemotely  = "missing remotely"
+	// StatusDiffers is reported if the file differs, but neither side is
+	// known to be unchanged since the last pull or push.
+	StatusDiffers = "differs"
+)
+
+type StatusCommand struct {
+	phrase.Config
+	Branch             string
+	UseLocalBranchName bool
+}
+
+type statusEvent struct {
+	Event      string `json:"event"`
+	File       string `json:"file"`
+	Status     string `json:"status"`
+	LocaleID   string `json:"locale_id,omitempty"`
+	LocaleName string `json:"locale_name,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Added      *int   `json:"added,omitempty"`
+	Removed    *int   `json:"removed,omitempty"`
+	Changed    *int   `json:"changed,omitempty"`
+}
+
+// fileStatus is the result of comparing a local locale file with its
+// counterpart in Phrase.
+type fileStatus struct {
+	localeFile *LocaleFile
+	status     string
+	diff       *keys.Diff
+}
+
+func (cmd *StatusCommand) Run() error {
+	if cmd.Config.Debug {
+		// suppresses content output
+		cmd.Config.Debug = false
+		Debug = true
+	}
+	Config = &cmd.Config
+
+	if len(cmd.Config.Sources) == 0 && len(cmd.Config.Targets) == 0 {
+		return fmt.Errorf("no sources or targets specified")
+	}
+
+	branchName, err := usedBranchName(cmd.UseLocalBranchName, cmd.Branch)
+	if err != nil {
+		return err
+	}
+	cmd.Branch = branchName
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		return err
+	}
+
+	statuses := []*fileStatus{}
+	seen := map[string]bool{}
+
+	if len(cmd.Config.Targets) > 0 {
+		targets, err := TargetsFromConfig(cmd.Config)
+		if err != nil {
+			return err
+		}
+
+		targetStatuses, err := targets.status(cmd.Branch, lock)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range targetStatuses {
+			seen[status.localeFile.Path] = true
+		}
+		statuses = append(statuses, targetStatuses...)
+	}
+
+	if len(cmd.Config.Sources) > 0 {
+		sources, err := SourcesFromConfig(cmd.Config)
+		if err != nil {
+			return err
+		}
+
+		if err := sources.Validate(); err != nil {
+			return err
+		}
+
+		sourceStatuses, err := sources.status(cmd.Branch, seen, lock)
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, sourceStatuses...)
+	}
+
+	return printStatus(statuses)
+}
+
+// status compares the files every target would be pulled to with the remote
+// locales. lock holds the state of the files when they were last pulled or
+// pushed.
+func (targets Targets) status(branch string, lock *LockFile) ([]*fileStatus, error) {
+	projectIdToLocales, err := LocalesForProjects(targets, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []*fileStatus{}
+	for _, target := range targets {
+		if err := target.CheckPreconditions(); err != nil {
+			return nil, err
+		}
+
+		target.RemoteLocales = projectIdToLocales[LocaleCacheKey{target.ProjectID, branch}]
+		target.branch = branch
+		if len(target.RemoteLocales) == 0 {
+			// the branch does not exist in this target's project
+			continue
+		}
+
+		localeFiles, err := target.LocaleFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, localeFile := range localeFiles {
+			status, err := target.status(target.Client(), localeFile, branch, lock)
+			if err != nil {
+				return nil, fmt.Errorf("%s for %s", err, localeFile.RelPath())
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+func (target *Target) status(client *phrase.APIClient, localeFile *LocaleFile, branch string, lock *LockFile) (*fileStatus, error) {
+	remote, err := target.Download(client, localeFile, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteLocale := remoteLocaleByID(target.RemoteLocales, localeFile.ID)
+	return compareLocaleFile(localeFile, remote, newSyncState(lock, localeFile, target.ProjectID, branch, remoteLocale))
+}
+
+// status compares the local files of every source with the remote locales.
+// Files contained in skip were already compared as pull targets.
+func (sources Sources) status(branch string, skip map[string]bool, lock *LockFile) ([]*fileStatus, error) {
+	projectIdToLocales, err := LocalesForProjects(sources, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []*fileStatus{}
+	for _, source := range sources {
+		source.RemoteLocales = projectIdToLocales[LocaleCacheKey{source.ProjectID, branch}]
+		source.branch = branch
+
+		localeFiles, err := source.LocaleFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		// download with the settings of the source, not those of a target
+		target := &Target{
+			File:          source.File,
+			ProjectID:     source.ProjectID,
+			FileFormat:    source.GetFileFormat(),
+			RemoteLocales: source.RemoteLocales,
+		}
+
+		for _, localeFile := range localeFiles {
+			if skip[localeFile.Path] {
+				continue
+			}
+
+			if !localeFile.ExistsRemote {
+				statuses = append(statuses, &fileStatus{localeFile: localeFile, status: StatusMissingRemotely})
+				continue
+			}
+
+			localeFile.FileFormat = target.FileFormat
+			status, err := target.status(source.Client(), localeFile, branch, lock)
+			if err != nil {
+				return nil, fmt.Errorf("%s for %s", err, localeFile.RelPath())
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+// syncState is what the lock file recorded about a local file when it was
+// last pulled or pushed.
+type syncState struct {
+	download *DownloadState
+	upload   *UploadState
+	// updatedAt is the last update of the remote locale.
+	updatedAt time.Time
+}
+
+// newSyncState returns the state recorded in lock for localeFile in the
+// project and branch, with the last update of remoteLocale, which may be nil.
+func newSyncState(lock *LockFile, localeFile *LocaleFile, projectID, branch string, remoteLocale *phrase.Locale) *syncState {
+	state := &syncState{}
+	if lock == nil {
+		return state
+	}
+
+	if download := lock.download(localeFile.Path); download != nil && download.LocaleID == localeFile.ID && download.Branch == branch {
+		state.download = download
+	}
+	state.upload = lock.upload(localeFile.Path, projectID, branch)
+	if remoteLocale != nil {
+		state.updatedAt = remoteLocale.UpdatedAt
+	}
+	return state
+}
+
+// changes reports which side changed since the last pull or push. The local
+// file is unchanged if its hash is the one recorded by either. The remote
+// locale is unchanged if it is still what was pulled, or was not updated
+// since the push. known is false if the file was neither pulled nor pushed.
+func (state *syncState) changes(local, remote []byte) (localChanged, remoteChanged, known bool) {
+	localChanged, remoteChanged = true, true
+	localHash := sha256Hex(local)
+
+	if state.download != nil {
+		known = true
+		if localHash == state.download.SHA256 {
+			localChanged = false
+		}
+		if sha256Hex(remote) == state.download.SHA256 {
+			remoteChanged = false
+		}
+	}
+
+	if state.upload != nil {
+		known = true
+		if localHash == state.upload.SHA256 {
+			localChanged = false
+		}
+		if !state.updatedAt.After(state.upload.UploadedAt) {
+			remoteChanged = false
+		}
+	}
+
+	return localChanged, remoteChanged, known
+}
+
+// compareLocaleFile compares the local content of localeFile with remote, the
+// content downloaded from its remote locale. Which side was modified is
+// decided by comparing both with state.
+func compareLocaleFile(localeFile *LocaleFile, remote []byte, state *syncState) (*fileStatus, error) {
+	status := &fileStatus{localeFile: localeFile}
+
+	local, err := ioutil.ReadFile(localeFile.Path)
+	if os.IsNotExist(err) {
+		status.status = StatusMissingLocally
+		return status, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if format, ok := keys.DetectFormat(localeFile.FileFormat, localeFile.Path); ok {
+		status.diff = compareKeys(format, remote, local)
+	}
+
+	if bytes.Equal(local, remote) || status.diff != nil && status.diff.Empty() {
+		status.status = StatusInSync
+		return status, nil
+	}
+
+	localChanged, remoteChanged, known := state.changes(local, remote)
+	switch {
+	case !known:
+		status.status = StatusDiffers
+	case localChanged && remoteChanged:
+		status.status = StatusModifiedBoth
+	case localChanged:
+		status.status = StatusLocallyModified
+	case remoteChanged:
+		status.status = StatusRemotelyModified
+	default:
+		status.status = StatusDiffers
+	}
+
+	return status, nil
+}
+
+// compareKeys returns the keys added, removed or changed locally, or nil if
+// either side cannot be parsed.
+func compareKeys(format keys.Format, remote, local []byte) *keys.Diff {
+	remoteKeys, err := keys.Parse(format, remote)
+	if err != nil {
+		return nil
+	}
+
+	localKeys, err := keys.Parse(format, local)
+	if err != nil {
+		return nil
+	}
+
+	diff := keys.Compare(remoteKeys, localKeys)
+	return &diff
+}
+
+func remoteLocaleByID(locales []*phrase.Locale, id string) *phrase.Locale {
+	for _, locale := range locales {
+		if locale.Id == id {
+			return locale
+		}
+	}
+	return nil
+}
+
+func printStatus(statuses []*fileStatus) error {
+	w := tabwriter.NewWriter(print.Out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSTATUS\tLOCALE\tTAG\tADDED\tREMOVED\tCHANGED")
+
+	for _, status := range statuses {
+		event := statusEvent{
+			Event:      "status",
+			File:       status.localeFile.RelPath(),
+			Status:     status.status,
+			LocaleID:   status.localeFile.ID,
+			LocaleName: status.localeFile.Name,
+			Tag:        status.localeFile.Tag,
+		}
+
+		added, removed, changed := "-", "-", "-"
+		if status.diff != nil {
+			event.Added = intPtr(len(status.diff.Added))
+			event.Removed = intPtr(len(status.diff.Removed))
+			event.Changed = intPtr(len(status.diff.Changed))
+			added = fmt.Sprint(*event.Added)
+			removed = fmt.Sprint(*event.Removed)
+			changed = fmt.Sprint(*event.Changed)
+		}
+		print.Event(event)
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			event.File,
+			event.Status,
+			orDash(event.LocaleName),
+			orDash(event.Tag),
+			added,
+			removed,
+			changed,
+		)
+	}
+
+	return w.Flush()
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompareLocaleFile(t *testing.T) {
+	dir := inTempDir(t)
+
+	pulled := []byte(`{"hello": "Hello"}`)
+	edited := []byte(`{"hello": "Hi"}`)
+	updated := []byte(`{"hello": "Hey"}`)
+	uploadedAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		local, remote []byte
+		state         *syncState
+		expected      string
+	}{
+		{"same content", pulled, pulled, &syncState{}, StatusInSync},
+		{"missing", nil, pulled, &syncState{}, StatusMissingLocally},
+		{"never synced", edited, pulled, &syncState{}, StatusDiffers},
+		{
+			"edited after pull", edited, pulled,
+			&syncState{download: &DownloadState{SHA256: sha256Hex(pulled)}},
+			StatusLocallyModified,
+		}, {
+			"updated after pull", pulled, updated,
+			&syncState{download: &DownloadState{SHA256: sha256Hex(pulled)}},
+			StatusRemotelyModified,
+		}, {
+			"edited and updated after pull", edited, updated,
+			&syncState{download: &DownloadState{SHA256: sha256Hex(pulled)}},
+			StatusModifiedBoth,
+		}, {
+			"edited after push", edited, pulled,
+			&syncState{upload: &UploadState{SHA256: sha256Hex(pulled), UploadedAt: uploadedAt}, updatedAt: uploadedAt.Add(-time.Minute)},
+			StatusLocallyModified,
+		}, {
+			"updated after push", pulled, updated,
+			&syncState{upload: &UploadState{SHA256: sha256Hex(pulled), UploadedAt: uploadedAt}, updatedAt: uploadedAt.Add(time.Minute)},
+			StatusRemotelyModified,
+		}, {
+			"edited after pull, pushed since", edited, edited,
+			&syncState{download: &DownloadState{SHA256: sha256Hex(pulled)}, upload: &UploadState{SHA256: sha256Hex(edited), UploadedAt: uploadedAt}},
+			StatusInSync,
+		}, {
+			"updated after push, pulled since", edited, pulled,
+			&syncState{download: &DownloadState{SHA256: sha256Hex(pulled)}, upload: &UploadState{SHA256: sha256Hex(updated), UploadedAt: uploadedAt}, updatedAt: uploadedAt.Add(time.Minute)},
+			StatusLocallyModified,
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name+".json")
+		if test.local != nil {
+			if err := ioutil.WriteFile(path, test.local, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		status, err := compareLocaleFile(&LocaleFile{Path: path}, test.remote, test.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.status != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, status.status)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// DefaultFileMode is the mode of files created by pull unless configured
+// otherwise.
+const DefaultFileMode os.FileMode = 0644
+
+// BackupSuffix is appended to the path of a file to get the path its previous
+// version is kept at by pull --backup.
+const BackupSuffix = ".bak"
+
+// writeFile replaces the file at path with data. An existing file keeps its
+// mode, new files are created with the configured mode of target.
+func (target *Target) writeFile(path string, data []byte) error {
+	mode := target.fileMode
+	if mode == 0 {
+		mode = DefaultFileMode
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+
+		if target.backup {
+			previous, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := writeFileAtomic(path+BackupSuffix, previous, mode); err != nil {
+				return err
+			}
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	return writeFileAtomic(path, data, mode)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place once everything was written, so that readers of path never see
+// a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// does nothing once the file was renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	pull "github.com/phrase/phrase-cli/cmd/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,9 +20,26 @@ func initPull() {
 		Short: "Pull translation changes",
 		Long:  "",
 		Run: func(cmd *cobra.Command, args []string) {
+			var interval time.Duration
+			if value := params.GetString("interval"); value != "" {
+				var err error
+				interval, err = time.ParseDuration(value)
+				if err != nil {
+					HandleError(fmt.Errorf("invalid interval %q: %s", value, err))
+				}
+			}
+
 			cmdPull := pull.PullCommand{
-				Branch:             params.GetString("branch"),
+				Branch:             branchParam(params),
 				UseLocalBranchName: params.GetBool("use-local-branch-name"),
+				DryRun:             params.GetBool("dry-run"),
+				Parallel:           params.GetInt("parallel"),
+				Watch:              params.GetBool("watch"),
+				Interval:           interval,
+				Backup:             params.GetBool("backup"),
+				Only:               params.GetString("only"),
+				Locale:             params.GetString("locale"),
+				OnlyTag:            onlyTagParam(params),
 			}
 			err := cmdPull.Run(Config)
 			if err != nil {
@@ -31,5 +51,15 @@ func initPull() {
 
 	AddFlag(pullCmd, "string", "branch", "b", "branch", false)
 	AddFlag(pullCmd, "bool", "use-local-branch-name", "", "use local branch name", false)
+	AddFlag(pullCmd, "int", "parallel", "", "Number of files to download concurrently (default 1)", false)
+	AddFlag(pullCmd, "bool", "dry-run", "", "Show which files would be written without downloading anything", false)
+	AddFlag(pullCmd, "bool", "watch", "", "Keep running and download locales again whenever they change in Phrase", false)
+	AddFlag(pullCmd, "string", "interval", "", "How often to check for changed locales with --watch, e.g. 1m (default 30s)", false)
+	AddFlag(pullCmd, "bool", "backup", "", "Keep the previous version of every overwritten file with a .bak suffix", false)
+	AddFlag(pullCmd, "string", "only", "", "Only pull the targets with these names, separated by commas", false)
+	AddFlag(pullCmd, "string", "locale", "", "Only pull files of the locales with these names, codes or ids, separated by commas", false)
+	AddFlag(pullCmd, "string", "only-tag", "", "Only pull files of these tags, separated by commas", false)
+	AddFlag(pullCmd, "string", "tag", "", "Only pull files of these tags, separated by commas", false)
+	pullCmd.Flags().MarkDeprecated("tag", "use --only-tag instead")
 	params.BindPFlags(pullCmd.Flags())
 }
@@ -15,14 +15,28 @@ func initPush() {
 	var pushCmd = &cobra.Command{
 		Use:   "push",
 		Short: "Push translation changes",
-		Long:  "",
+		Long: `Push translation changes
+
+The file patterns of sources support * for any part of a file name, ** for
+any number of directories, {a,b} for one of several alternatives and [a-z]
+or [!a-z] for a character of a class. A [ which is closed by ] within the
+same directory starts a character class, escape it as \[ to match a
+literal bracket.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdPush := push.PushCommand{
 				Config:             *Config,
 				Wait:               params.GetBool("wait"),
-				Branch:             params.GetString("branch"),
+				Branch:             branchParam(params),
 				UseLocalBranchName: params.GetBool("use-local-branch-name"),
 				Tag:                params.GetString("tag"),
+				DryRun:             params.GetBool("dry-run"),
+				Parallel:           params.GetInt("parallel"),
+				FailFast:           params.GetBool("fail-fast"),
+				Watch:              params.GetBool("watch"),
+				Force:              params.GetBool("force"),
+				Only:               params.GetString("only"),
+				Locale:             params.GetString("locale"),
+				OnlyTag:            params.GetString("only-tag"),
 			}
 			err := cmdPush.Run()
 			if err != nil {
@@ -36,5 +50,13 @@ func initPush() {
 	AddFlag(pushCmd, "string", "branch", "b", "branch", false)
 	AddFlag(pushCmd, "bool", "use-local-branch-name", "", "push from the branch with the name of your currently checked out branch (git or mercurial)", false)
 	AddFlag(pushCmd, "string", "tag", "", "Pre tag the uploaded keys with a specific tag", false)
+	AddFlag(pushCmd, "int", "parallel", "", "Number of files to upload concurrently (default 1)", false)
+	AddFlag(pushCmd, "bool", "fail-fast", "", "Stop at the first file that could not be uploaded or processed", false)
+	AddFlag(pushCmd, "bool", "dry-run", "", "Show which files would be uploaded to which locales without uploading anything", false)
+	AddFlag(pushCmd, "bool", "watch", "", "Keep running and upload source files again whenever they change", false)
+	AddFlag(pushCmd, "bool", "force", "", "Upload all files, including those which did not change since the last push", false)
+	AddFlag(pushCmd, "string", "only", "", "Only push the sources with these names, separated by commas", false)
+	AddFlag(pushCmd, "string", "locale", "", "Only push files of the locales with these names, codes or ids, separated by commas", false)
+	AddFlag(pushCmd, "string", "only-tag", "", "Only push files uploaded with one of these tags, separated by commas", false)
 	params.BindPFlags(pushCmd.Flags())
 }
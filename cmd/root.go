@@ -3,10 +3,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/bgentry/speakeasy"
+	"github.com/phrase/phrase-cli/cmd/internal"
+	"github.com/phrase/phrase-cli/cmd/internal/print"
+	"github.com/phrase/phrase-cli/cmd/internal/ratelimit"
+	"github.com/phrase/phrase-cli/cmd/internal/spinner"
 	"github.com/phrase/phrase-cli/cmd/internal/updatechecker"
 	"github.com/phrase/phrase-go"
 	api "github.com/phrase/phrase-go"
@@ -16,8 +21,14 @@ import (
 
 var (
 	// Used for flags.
-	cfgFile string
-	Config  *phrase.Config
+	cfgFile      string
+	profileName  string
+	outputFormat string
+	Config       *phrase.Config
+	// ConfigOrigins records where the settings of Config came from.
+	ConfigOrigins *internal.ConfigOrigins
+	// Profile is the profile merged into Config, or nil.
+	Profile *internal.ConfigProfile
 
 	rootCmd = &cobra.Command{
 		Use:   "phrase",
@@ -32,6 +43,10 @@ func init() {
 		UserAgent: "Phrase CLI (" + PHRASE_CLIENT_VERSION + ")",
 	}
 
+	// the generated API commands send their requests with http.DefaultClient,
+	// so it shares the rate limit and retries of the other commands
+	http.DefaultClient.Transport = ratelimit.DefaultTransport
+
 	cobra.OnInitialize(initConfig)
 	cobra.OnInitialize(checkUpdate)
 
@@ -55,7 +70,13 @@ func init() {
 	viper.BindPFlag("tfa", rootCmd.PersistentFlags().Lookup("tfa"))
 	viper.SetDefault("tfa", false)
 
+	rootCmd.PersistentFlags().DurationVar(&ratelimit.DefaultTransport.MaxWait, "max-retry-wait", ratelimit.DefaultMaxWait, "maximum time a request may wait for the rate limit and retries")
+
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "output format of push and pull: text or json")
+
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./.phrase.yml fallback to $HOME/.phrase.yml)")
+
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "profile of the config file to use (default is $"+internal.ProfileEnv+")")
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -95,50 +116,108 @@ func AddFlag(cmd *cobra.Command, flagType string, name string, short string, des
 }
 
 func initConfig() {
-	config, err := phrase.ReadConfig(cfgFile)
+	switch outputFormat {
+	case "text":
+	case "json":
+		print.EnableJSON()
+		spinner.Disabled = true
+	default:
+		HandleError(fmt.Errorf("unknown output format %q, must be text or json", outputFormat))
+	}
+
+	profileOrigin := "--profile flag"
+	if profileName == "" {
+		profileName = os.Getenv(internal.ProfileEnv)
+		profileOrigin = internal.ProfileEnv + " environment variable"
+	}
+
+	// the path is recorded where it is read, for config show
+	cfgPath, cfgOrigin, err := internal.FindConfigFile(cfgFile)
 	if err != nil {
 		HandleError(err)
 	}
+	config, profile, err := internal.ReadConfig(cfgPath, profileName)
+	if err != nil {
+		HandleError(err)
+	}
+	internal.ConfigFile = cfgPath
+
+	ConfigOrigins = internal.NewConfigOrigins(config, cfgPath, cfgOrigin)
+	if profile != nil {
+		profile.Origin = profileOrigin
+		ConfigOrigins.SetProfile(profile)
+		Profile = profile
+	}
 
 	// flag overwrites debug option from file
 	if Config.Debug {
 		config.Debug = Config.Debug
+		ConfigOrigins.Set("debug", "--verbose flag")
 	}
 	config.UserAgent = Config.UserAgent
 
 	if Config.Credentials.Host != "" {
 		config.Credentials.Host = Config.Credentials.Host
+		ConfigOrigins.Set("host", "--host flag")
 	}
 
 	if Config.Credentials.Token != "" {
 		config.Credentials.Token = Config.Credentials.Token
+		ConfigOrigins.Set("access_token", "--access_token flag")
 	}
 
 	if Config.Credentials.Username != "" {
 		config.Credentials.Username = Config.Credentials.Username
+		ConfigOrigins.Set("username", "--username flag")
 	}
 
 	phraseappAccessToken := os.Getenv("PHRASEAPP_ACCESS_TOKEN")
 	if phraseappAccessToken != "" && config.Credentials.Token == "" && config.Credentials.Username == "" {
 		config.Credentials.Token = phraseappAccessToken
+		ConfigOrigins.Set("access_token", "PHRASEAPP_ACCESS_TOKEN environment variable")
 	}
 
 	phraseAccessToken := os.Getenv("PHRASE_ACCESS_TOKEN")
 	if phraseAccessToken != "" && config.Credentials.Token == "" && config.Credentials.Username == "" {
 		config.Credentials.Token = phraseAccessToken
+		ConfigOrigins.Set("access_token", "PHRASE_ACCESS_TOKEN environment variable")
 	}
 
 	if Config.Credentials.TFA {
 		config.Credentials.TFA = Config.Credentials.TFA
+		ConfigOrigins.Set("tfa", "--tfa flag")
 	}
 
 	if config.Debug {
-		fmt.Printf("%+v\n", config)
+		// tokens are redacted, as verbose output often ends up in CI logs
+		if err := internal.PrintConfig(print.Out(), config, ConfigOrigins); err != nil {
+			print.Error(err)
+		}
 	}
 
 	Config = config
 }
 
+// branchParam returns the value of the branch flag of params, or the branch
+// of the selected profile if neither a branch nor the local branch name is
+// requested.
+func branchParam(params *viper.Viper) string {
+	branch := params.GetString("branch")
+	if branch != "" || params.GetBool("use-local-branch-name") || Profile == nil {
+		return branch
+	}
+	return Profile.Branch
+}
+
+// onlyTagParam returns the value of the only-tag flag of params, or of tag,
+// its deprecated name on pull and diff.
+func onlyTagParam(params *viper.Viper) string {
+	if tag := params.GetString("only-tag"); tag != "" {
+		return tag
+	}
+	return params.GetString("tag")
+}
+
 func Auth() context.Context {
 	if Config.Credentials.Token != "" {
 		return context.WithValue(context.Background(), api.ContextAPIKey, api.APIKey{
@@ -181,6 +260,22 @@ func checkUpdate() {
 }
 
 func HandleError(msg interface{}) {
+	exitCode := 1
+	if err, ok := msg.(interface{ ExitCode() int }); ok {
+		exitCode = err.ExitCode()
+	}
+
+	if print.JSON() {
+		print.Event(errorEvent{Event: "error", Message: fmt.Sprint(msg)})
+		fmt.Fprintln(os.Stderr, "Error:", msg)
+		os.Exit(exitCode)
+	}
+
 	fmt.Println("Error:", msg)
-	os.Exit(1)
+	os.Exit(exitCode)
+}
+
+type errorEvent struct {
+	Event   string `json:"event"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	status "github.com/phrase/phrase-cli/cmd/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	initStatus()
+}
+
+func initStatus() {
+	params := viper.New()
+	var statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Compare local locale files with Phrase",
+		Long:  "Show for every source and target file whether it is in sync with Phrase, modified locally or remotely, or missing on either side. Which side was modified is decided by the state of the file recorded in .phrase.lock by the last pull or push, and the last update of the locale in Phrase.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdStatus := status.StatusCommand{
+				Config:             *Config,
+				Branch:             branchParam(params),
+				UseLocalBranchName: params.GetBool("use-local-branch-name"),
+			}
+			err := cmdStatus.Run()
+			if err != nil {
+				HandleError(err)
+			}
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+
+	AddFlag(statusCmd, "string", "branch", "b", "branch", false)
+	AddFlag(statusCmd, "bool", "use-local-branch-name", "", "use local branch name", false)
+	params.BindPFlags(statusCmd.Flags())
+}